@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func BenchmarkE(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = E("test error", WithMeta("key", "val"))
+	}
+}
+
+func BenchmarkENoSource(b *testing.B) {
+	SetSourceCapture(CaptureNever)
+	defer SetSourceCapture(CaptureAlways)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = E("test error", WithMeta("key", "val"))
+	}
+}
+
+func BenchmarkEWithStack(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = E("test error", WithStack)
+	}
+}
+
+func BenchmarkM(b *testing.B) {
+	err := E("test error", WithMeta("key", "val"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = M(err, WithMeta("key2", "val2"))
+	}
+}
+
+func BenchmarkSourceString(b *testing.B) {
+	e := E("test error").(*Error)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = e.Source.String()
+	}
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	err := E("error 3", E("error 2", E("error 1", WithMeta("key", "val"))))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = Flatten(err)
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	err := E("error 3", E("error 2", E("error 1", WithMeta("key", "val"))))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = json.Marshal(err)
+	}
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SourceCapture sets when the Source of an error is recorded, see SetSourceCapture.
+type SourceCapture uint8
+
+// Source capture modes.
+const (
+	CaptureAlways  SourceCapture = iota // Record the source of every error, the default.
+	CaptureNever                        // Never record the source, errors have the zero Source.
+	CaptureSampled                      // Record the source of 1 in N errors, see SetSourceSampleRate.
+	CaptureDebug                        // Record the source only in binaries built with the errorsdebug build tag.
+)
+
+// defaultSampleRate is the sample rate used by CaptureSampled when SetSourceSampleRate wasn't called.
+const defaultSampleRate = 100
+
+var (
+	// sourceCapture is the global source capture mode.
+	sourceCapture atomic.Uint32
+
+	// sourceSampleRate and sourceSampleCount select the sampled errors in CaptureSampled mode, a zero rate means
+	// defaultSampleRate.
+	sourceSampleRate  atomic.Uint64
+	sourceSampleCount atomic.Uint64
+
+	// frameCache maps a program counter to its symbolized frames, there can be more than one frame if functions were
+	// inlined at the program counter.
+	frameCache sync.Map
+
+	// displayCache maps a file and function pair to the file path returned by Source.File, it's created on first use and
+	// replaced when the trimming settings change.
+	displayCache atomic.Pointer[sync.Map]
+)
+
+// SetSourceCapture sets when E, M and Template.New record the Source of an error. Recording the source walks the call
+// stack on every call, CaptureNever, CaptureSampled or CaptureDebug can be used to lower the cost on hot paths. Errors
+// without a source still work as usual, their Source is the zero value and it's left out of every output.
+func SetSourceCapture(mode SourceCapture) {
+	sourceCapture.Store(uint32(mode))
+}
+
+// SetSourceSampleRate sets the rate used by CaptureSampled, the source is recorded for 1 in n errors. Values lower than
+// 1 are set to 1. The default rate is 100.
+func SetSourceSampleRate(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	sourceSampleRate.Store(uint64(n))
+}
+
+// shouldCaptureSource reports whether the source of a new error must be recorded based on the global capture mode.
+func shouldCaptureSource() bool {
+	switch SourceCapture(sourceCapture.Load()) {
+	case CaptureNever:
+		return false
+
+	case CaptureSampled:
+		rate := sourceSampleRate.Load()
+		if rate == 0 {
+			rate = defaultSampleRate
+		}
+
+		return sourceSampleCount.Add(1)%rate == 0
+
+	case CaptureDebug:
+		return debugBuild
+	}
+
+	return true
+}
+
+// cachedFrame is a symbolized frame stored in frameCache.
+type cachedFrame struct {
+	file     string
+	line     int
+	function string
+}
+
+// displayFile returns the file path of f with the trimming and rewrite rules applied, see Source.File.
+func (f cachedFrame) displayFile() string {
+	if len(f.function) == 0 || f.function == "unknown" {
+		return f.file
+	}
+
+	return cachedDisplayPath(f.file, f.function)
+}
+
+// symbolize returns the frames of the program counter pc returned by runtime.Callers, the result is cached.
+func symbolize(pc uintptr) []cachedFrame {
+	if v, ok := frameCache.Load(pc); ok {
+		frames, _ := v.([]cachedFrame)
+		return frames
+	}
+
+	var frames []cachedFrame
+
+	callersFrames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := callersFrames.Next()
+		frames = append(frames, cachedFrame{file: f.File, line: f.Line, function: f.Function})
+
+		if !more {
+			break
+		}
+	}
+
+	frameCache.Store(pc, frames)
+
+	return frames
+}
+
+// cachedDisplayPath returns displayPath of file and function using displayCache.
+func cachedDisplayPath(file, function string) string {
+	cache := displayCache.Load()
+	if cache == nil {
+		displayCache.CompareAndSwap(nil, &sync.Map{})
+		cache = displayCache.Load()
+	}
+
+	key := function + "\x00" + file
+
+	if v, ok := cache.Load(key); ok {
+		p, _ := v.(string)
+		return p
+	}
+
+	p := displayPath(file, function)
+	cache.Store(key, p)
+
+	return p
+}
+
+// resetDisplayCache drops the cached file paths, it must be called after the trimming settings change.
+func resetDisplayCache() {
+	displayCache.Store(&sync.Map{})
+}
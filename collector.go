@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"sync"
+)
+
+// MetaKeyDropped is the Meta key holding the number of errors dropped by a Collector, see CollectorOptions.MaxErrors.
+const MetaKeyDropped = "dropped"
+
+// defaultCollectorMsg is the message of the error returned by Collector.Err when no message is configured.
+const defaultCollectorMsg = "multiple errors"
+
+// CollectorOptions configures a Collector.
+type CollectorOptions struct {
+	// Msg is the message of the error returned by Err, defaults to "multiple errors".
+	Msg string
+
+	// MaxErrors is the maximum number of errors kept, the errors added after the limit is reached are counted in the
+	// MetaKeyDropped Meta key of the error returned by Err. Zero or negative means no limit.
+	MaxErrors int
+
+	// Dedup skips the errors with the same message and Source as an error already collected, ex. the same failure
+	// reported by many workers.
+	Dedup bool
+}
+
+// Collector accumulates errors from multiple goroutines and returns them as the causes of a single error. The zero
+// value is ready to use with the default options. A Collector is safe for concurrent use.
+type Collector struct {
+	mu      sync.Mutex
+	opts    CollectorOptions
+	errs    []error
+	seen    map[string]struct{}
+	dropped int
+}
+
+// NewCollector returns a new Collector. opts can be nil.
+func NewCollector(opts *CollectorOptions) *Collector {
+	c := &Collector{}
+
+	if opts != nil {
+		c.opts = *opts
+	}
+
+	return c
+}
+
+// Add adds err to the collected errors and reports whether it was kept. A nil err, a duplicate if Dedup is set or an
+// error over the MaxErrors limit is not kept.
+func (c *Collector) Add(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.opts.Dedup {
+		key := dedupKey(err)
+		if _, has := c.seen[key]; has {
+			return false
+		}
+
+		if c.seen == nil {
+			c.seen = make(map[string]struct{})
+		}
+
+		c.seen[key] = struct{}{}
+	}
+
+	if c.opts.MaxErrors > 0 && len(c.errs) >= c.opts.MaxErrors {
+		c.dropped++
+		return false
+	}
+
+	c.errs = append(c.errs, err)
+
+	return true
+}
+
+// Len returns the number of collected errors.
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.errs)
+}
+
+// Errors returns a copy of the collected errors in the order they were added.
+func (c *Collector) Errors() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]error(nil), c.errs...)
+}
+
+// Err returns nil if no errors were collected, otherwise a new error with the Source set to the caller and every
+// collected error as its causes. If errors were dropped because of MaxErrors their number is set in Meta under
+// MetaKeyDropped.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.errs) == 0 {
+		return nil
+	}
+
+	msg := c.opts.Msg
+	if len(msg) == 0 {
+		msg = defaultCollectorMsg
+	}
+
+	var args []any
+	if c.dropped > 0 {
+		args = append(args, WithMeta(MetaKeyDropped, c.dropped))
+	}
+
+	e := newError(1, msg, args)
+
+	// The causes are set directly, passing them as args would parse errors like a Kind as settings.
+	if len(c.errs) == 1 {
+		e.err = c.errs[0]
+	} else {
+		e.err = &joinError{errs: append([]error(nil), c.errs...)}
+	}
+
+	runHooks(e)
+
+	return e
+}
+
+// dedupKey returns the message and the Source of the outermost errors.Error of err.
+func dedupKey(err error) string {
+	if e, ok := err.(*Error); ok {
+		return err.Error() + "\x00" + e.Source.String()
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("it should return nil without errors", collectNoErrors)
+	t.Run("it should collect errors from goroutines", collectConcurrently)
+	t.Run("it should limit the number of errors", collectMaxErrors)
+	t.Run("it should skip duplicates", collectDedup)
+	t.Run("it should render every collected error", renderCollected)
+}
+
+func collectNoErrors(t *testing.T) {
+	var c Collector
+	c.Add(nil)
+
+	if err := c.Err(); err != nil {
+		t.Fatalf("expected nil error, got: %s", err.Error())
+	}
+}
+
+func collectConcurrently(t *testing.T) {
+	c := NewCollector(nil)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.Add(E("worker failed"))
+		}()
+	}
+
+	wg.Wait()
+
+	if c.Len() != 50 {
+		t.Fatalf("expected 50 errors, got: %d", c.Len())
+	}
+
+	if errs := Flatten(c.Err()); len(errs) != 51 || errs[0].Msg != defaultCollectorMsg {
+		t.Fatalf("expected the collected errors as causes, got: %d", len(errs))
+	}
+}
+
+func collectMaxErrors(t *testing.T) {
+	c := NewCollector(&CollectorOptions{Msg: "import failed", MaxErrors: 2})
+
+	for i := 0; i < 5; i++ {
+		c.Add(errors.New("row failed"))
+	}
+
+	err := c.Err()
+
+	if err.Error() != "import failed" || len(Flatten(err)) != 3 {
+		t.Fatalf("expected 2 causes, got: %s", PrettyTree(err, nil))
+	}
+
+	if n, _ := LookupMeta(err, MetaKeyDropped); n != 3 {
+		t.Fatalf("expected 3 dropped errors, got: %+v", n)
+	}
+}
+
+func collectDedup(t *testing.T) {
+	c := NewCollector(&CollectorOptions{Dedup: true})
+
+	for i := 0; i < 3; i++ {
+		if kept := c.Add(E("same error")); kept != (i == 0) {
+			t.Fatalf("expected only the first error to be kept, got: %t at %d", kept, i)
+		}
+	}
+
+	c.Add(E("same error"))
+	c.Add(errors.New("std error"))
+	c.Add(errors.New("std error"))
+
+	if c.Len() != 3 {
+		t.Fatalf("expected 3 errors, got: %d", c.Len())
+	}
+}
+
+func renderCollected(t *testing.T) {
+	var c Collector
+	c.Add(E("error 1", NotFound))
+	c.Add(errors.New("error 2"))
+	c.Add(Conflict)
+
+	err := c.Err()
+
+	for _, s := range []string{PrettyPrint(err), PrettyTree(err, nil)} {
+		if !strings.Contains(s, "error 1") || !strings.Contains(s, "error 2") {
+			t.Fatalf("expected every collected error in the output, got:\n%s", s)
+		}
+	}
+
+	b, _ := json.Marshal(err)
+
+	var cmp []map[string]any
+	if jerr := json.Unmarshal(b, &cmp); jerr != nil || len(cmp) != 4 {
+		t.Fatalf("expected 4 JSON errors, got: %s", string(b))
+	}
+
+	if !Is(err, NotFound) || !Is(err, Conflict) {
+		t.Fatalf("expected the causes to be matched")
+	}
+}
@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+)
+
+// contextMetaKey is the context key of the Meta stored by ContextWithMeta.
+type contextMetaKey struct{}
+
+// ContextExtractor returns the Meta of values stored in a context by other packages, ex. a request or a trace ID. See
+// AddContextExtractor.
+type ContextExtractor func(ctx context.Context) Meta
+
+// contextExtractors holds the registered extractors. The slice is replaced on every change so it can be read without
+// locking.
+var contextExtractors = struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]ContextExtractor]
+}{}
+
+// ContextWithMeta returns a copy of ctx carrying the key/value pairs merged with the Meta already stored in ctx. The
+// arguments are parsed the same way as in WithMeta. Errors created with EC and MC get the Meta of the context.
+func ContextWithMeta(ctx context.Context, firstKey string, args ...any) context.Context {
+	add := WithMeta(firstKey, args...)
+
+	current, _ := ctx.Value(contextMetaKey{}).(Meta)
+
+	m := make(Meta, len(current)+len(add))
+	for k, v := range current {
+		m[k] = v
+	}
+
+	for k, v := range add {
+		m[k] = v
+	}
+
+	return context.WithValue(ctx, contextMetaKey{}, m)
+}
+
+// AddContextExtractor registers fn to add Meta from values stored in a context by other packages, the extractors are
+// called by MetaFromContext in the order they were registered. AddContextExtractor is safe for concurrent use.
+func AddContextExtractor(fn ContextExtractor) {
+	contextExtractors.mu.Lock()
+	defer contextExtractors.mu.Unlock()
+
+	var list []ContextExtractor
+	if current := contextExtractors.list.Load(); current != nil {
+		list = append(list, *current...)
+	}
+
+	list = append(list, fn)
+	contextExtractors.list.Store(&list)
+}
+
+// MetaFromContext returns a new Meta with the values returned by the context extractors and the Meta stored in ctx
+// by ContextWithMeta, the latter takes precedence.
+func MetaFromContext(ctx context.Context) Meta {
+	m := make(Meta)
+
+	if ctx == nil {
+		return m
+	}
+
+	if list := contextExtractors.list.Load(); list != nil {
+		for _, fn := range *list {
+			for k, v := range fn(ctx) {
+				m[k] = v
+			}
+		}
+	}
+
+	if current, ok := ctx.Value(contextMetaKey{}).(Meta); ok {
+		for k, v := range current {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+// EC is the same as E but merges the Meta of ctx into the error, see MetaFromContext. The Meta passed in args takes
+// precedence over the Meta of ctx. If ctx is done, context.Cause is added to the causes of the error. ctx can be nil.
+func EC(ctx context.Context, msg string, args ...any) error {
+	e := newError(1, msg, args)
+	e.mergeContext(ctx)
+	runHooks(e)
+
+	return e
+}
+
+// MC is the same as M but merges the Meta of ctx into the error, see EC. The Meta of err and args take precedence over
+// the Meta of ctx.
+func MC(ctx context.Context, err error, args ...any) error {
+	e := mirror(1, err, args)
+	e.mergeContext(ctx)
+	runHooks(e)
+
+	return e
+}
+
+// mergeContext merges the Meta of ctx into e without overwriting the keys already set and adds the cause of a done ctx
+// to the causes of e, unless it's already part of the chain. A nil ctx is ignored, the same as in MetaFromContext.
+func (e *Error) mergeContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	for k, v := range MetaFromContext(ctx) {
+		if _, has := e.Meta[k]; has {
+			continue
+		}
+
+		if e.Meta == nil {
+			e.Meta = make(Meta)
+		}
+
+		e.Meta[k] = v
+	}
+
+	if ctx.Err() == nil {
+		return
+	}
+
+	cause := context.Cause(ctx)
+	if stderrors.Is(e, cause) {
+		return
+	}
+
+	switch c := e.err.(type) {
+	case nil:
+		e.err = cause
+
+	case *joinError:
+		e.err = &joinError{errs: append(append([]error(nil), c.errs...), cause)}
+
+	default:
+		e.err = &joinError{errs: []error{c, cause}}
+	}
+}
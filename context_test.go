@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type testTraceKey struct{}
+
+// sharedMeta is passed to the errors by many goroutines, it must never be written to.
+var sharedMeta = Meta{"service": "api"}
+
+func TestContextMeta(t *testing.T) {
+	t.Run("it should accumulate meta on the context", accumulateContextMeta)
+	t.Run("it should merge the context meta with EC", mergeContextMetaEC)
+	t.Run("it should merge the context meta with MC", mergeContextMetaMC)
+	t.Run("it should use the context extractors", useContextExtractors)
+	t.Run("it should add the cause of a done context", addContextCause)
+	t.Run("it should not write into the meta passed by the caller", keepCallerMeta)
+	t.Run("it should accept a nil context", acceptNilContext)
+}
+
+func accumulateContextMeta(t *testing.T) {
+	ctx := ContextWithMeta(context.Background(), "requestId", "r1")
+	ctx2 := ContextWithMeta(ctx, "tenantId", "t1", "requestId", "r2")
+
+	if m := MetaFromContext(ctx); len(m) != 1 || m["requestId"] != "r1" {
+		t.Fatalf("expected the parent context to be unchanged, got: %+v", m)
+	}
+
+	if m := MetaFromContext(ctx2); m["requestId"] != "r2" || m["tenantId"] != "t1" {
+		t.Fatalf("expected the meta to be accumulated, got: %+v", m)
+	}
+}
+
+func mergeContextMetaEC(t *testing.T) {
+	ctx := ContextWithMeta(context.Background(), "requestId", "r1", "userId", "u1")
+
+	err := EC(ctx, "test", WithMeta("userId", "u2"))
+	m, _ := GetMeta(err)
+
+	if m["requestId"] != "r1" || m["userId"] != "u2" {
+		t.Fatalf("expected the context meta with args taking precedence, got: %+v", m)
+	}
+
+	var e *Error
+	As(err, &e)
+
+	if e.Source.Function() != "github.com/primalskill/errors.mergeContextMetaEC" {
+		t.Fatalf("expected the source to be the caller, got: %s", e.Source.Function())
+	}
+}
+
+func mergeContextMetaMC(t *testing.T) {
+	ctx := ContextWithMeta(context.Background(), "requestId", "r1", "userId", "u1")
+
+	err := MC(ctx, E("test", WithMeta("userId", "u2")))
+	m, _ := GetMeta(err)
+
+	if m["requestId"] != "r1" || m["userId"] != "u2" {
+		t.Fatalf("expected the context meta with the error meta taking precedence, got: %+v", m)
+	}
+}
+
+func useContextExtractors(t *testing.T) {
+	AddContextExtractor(func(ctx context.Context) Meta {
+		if id, ok := ctx.Value(testTraceKey{}).(string); ok {
+			return WithMeta("traceId", id)
+		}
+
+		return nil
+	})
+
+	defer func() {
+		contextExtractors.mu.Lock()
+		contextExtractors.list.Store(nil)
+		contextExtractors.mu.Unlock()
+	}()
+
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "trace1")
+
+	m, _ := GetMeta(EC(ctx, "test"))
+	if m["traceId"] != "trace1" {
+		t.Fatalf("expected the extracted meta, got: %+v", m)
+	}
+}
+
+func addContextCause(t *testing.T) {
+	errCause := E("shutting down")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errCause)
+
+	err := EC(ctx, "cannot load user", E("query failed"))
+
+	if !Is(err, errCause) || !HasMessage(err, "query failed") {
+		t.Fatalf("expected the cause to be added to the chain, got: %s", PrettyTree(err, nil))
+	}
+
+	err = MC(ctx, err)
+
+	n := 0
+	Walk(err, func(wErr error, _ int) bool {
+		if wErr == errCause {
+			n++
+		}
+
+		return true
+	})
+
+	if n != 1 {
+		t.Fatalf("expected the cause to be added once, got: %d", n)
+	}
+
+	if err = EC(context.Background(), "test"); Unwrap(err) != nil {
+		t.Fatalf("expected no cause for a context which is not done")
+	}
+}
+
+func keepCallerMeta(t *testing.T) {
+	ctx := ContextWithMeta(context.Background(), "requestId", "r1")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = EC(ctx, "test", sharedMeta)
+			_ = MC(ctx, E("test"), sharedMeta)
+		}()
+	}
+
+	wg.Wait()
+
+	if len(sharedMeta) != 1 || sharedMeta["service"] != "api" {
+		t.Fatalf("expected the shared meta to be unchanged, got: %+v", sharedMeta)
+	}
+
+	m, _ := GetMeta(EC(ctx, "test", sharedMeta))
+	if m["requestId"] != "r1" || m["service"] != "api" {
+		t.Fatalf("expected the error meta to merge the shared and the context meta, got: %+v", m)
+	}
+}
+
+func acceptNilContext(t *testing.T) {
+	//nolint:staticcheck // A nil context is allowed.
+	err := EC(nil, "test", WithMeta("userId", "u1"))
+
+	m, _ := GetMeta(err)
+	if err.Error() != "test" || m["userId"] != "u1" || Unwrap(err) != nil {
+		t.Fatalf("expected the error without context meta, got: %s", PrettyTree(err, nil))
+	}
+}
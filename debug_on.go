@@ -0,0 +1,6 @@
+//go:build errorsdebug
+
+package errors
+
+// debugBuild is true in binaries built with the errorsdebug build tag, see CaptureDebug.
+const debugBuild = true
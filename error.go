@@ -5,40 +5,98 @@ import (
 )
 
 type Error struct {
-	withFlag error
-	err      error
-	Msg      string `json:"msg"`
-	Source   Source `json:"source,omitempty"`
-	Meta     Meta   `json:"meta,omitempty"`
+	withFlag  error
+	err       error
+	retry     Retryability
+	tmpl      *Template
+	tmplMsg   bool
+	decoded   bool
+	Msg       string     `json:"msg"`
+	PublicMsg string     `json:"publicMsg,omitempty"`
+	Kind      Kind       `json:"kind,omitempty"`
+	Code      Code       `json:"code,omitempty"`
+	Source    Source     `json:"source,omitempty"`
+	Meta      Meta       `json:"meta,omitempty"`
+	Stack     StackTrace `json:"stack,omitempty"`
 }
 
 // parseArgTypes parses the arguments passed to the function
 func (e *Error) parseArgTypes(args ...any) {
+	var causes []error
+
 	for _, arg := range args {
 		switch arg := arg.(type) {
 
 		case Meta:
-			// Merge the meta into the existing map on the err
-			if len(e.Meta) == 0 {
-				e.Meta = arg
-			} else {
-				for k, v := range arg {
-					e.Meta.Set(k, v)
-				}
+			// Merge the meta into the map of the err, the passed map is never stored so callers can share or reuse it.
+			if e.Meta == nil {
+				e.Meta = make(Meta, len(arg))
+			}
+
+			for k, v := range arg {
+				e.Meta.Set(k, v)
 			}
 
+		case Kind:
+			e.Kind = arg
+
+		case Code:
+			e.Code = arg
+
+		case Retryability:
+			e.retry = arg
+
+		case Public:
+			e.PublicMsg = string(arg)
+
+		case MessageMode:
+			e.tmplMsg = arg == TemplateMsg
+
 		case error:
-			e.err = arg
+			causes = append(causes, arg)
 		}
 	}
+
+	// Multiple causes are grouped so Unwrap() still returns a single error which exposes Unwrap() []error.
+	switch len(causes) {
+	case 0:
+	case 1:
+		e.err = causes[0]
+	default:
+		e.err = &joinError{errs: causes}
+	}
 }
 
-// E return a new error and sets the required msg argument as the error message. Additional arguments like a Meta map or another error can be passed
-// into the function that will be set on the error.
+// E return a new error and sets the required msg argument as the error message. Additional arguments like a Meta map,
+// a Kind, a Code or other errors can be passed into the function that will be set on the error. Passing multiple errors
+// sets all of them as the causes of the error. Pass WithStack to record the full call stack, see SetStackTrace.
 func E(msg string, args ...any) error {
+	e := newError(1, msg, args)
+	runHooks(e)
+
+	return e
+}
+
+// ESkip is the same as E but skips the given number of additional frames when attributing the Source and the Stack of
+// the error. ESkip(0, ...) is the same as E, ESkip(1, ...) attributes the error to the caller of the function calling
+// ESkip. See Helper to skip a function regardless of the call depth.
+func ESkip(skip int, msg string, args ...any) error {
+	e := newError(skip+1, msg, args)
+	runHooks(e)
+
+	return e
+}
+
+// newError creates the error for E, ESkip and EC, skip is the number of frames between the caller and newError. The
+// hooks are run by the callers once the error is complete.
+func newError(skip int, msg string, args []any) *Error {
 	e := &Error{}
 	e.Msg = msg
-	e.Source = getSource()
+	e.Source = getSource(skip)
+
+	if captureStack(args) {
+		e.Stack = getStack(skip)
+	}
 
 	e.parseArgTypes(args...)
 
@@ -48,6 +106,24 @@ func E(msg string, args ...any) error {
 // M preloads err with all its Meta and wrapped errors if err is of type Error, otherwise it creates a new error of type Error and
 // adds args on it. Passing in a regular error as err in the argument converts err to Error.
 func M(err error, args ...any) error {
+	e := mirror(1, err, args)
+	runHooks(e)
+
+	return e
+}
+
+// MSkip is the same as M but skips the given number of additional frames when attributing the Source and the Stack of
+// the error, see ESkip.
+func MSkip(skip int, err error, args ...any) error {
+	e := mirror(skip+1, err, args)
+	runHooks(e)
+
+	return e
+}
+
+// mirror creates the error for M, MSkip, With and MC, skip is the number of frames between the caller and mirror. The
+// hooks are run by the callers once the error is complete.
+func mirror(skip int, err error, args []any) *Error {
 	e := &Error{}
 	ec, is := err.(*Error)
 
@@ -59,6 +135,14 @@ func M(err error, args ...any) error {
 
 		e.err = ec.err
 		e.Msg = ec.Msg
+		e.PublicMsg = ec.PublicMsg
+		e.Kind = ec.Kind
+		e.Code = ec.Code
+		e.Stack = ec.Stack
+		e.retry = ec.retry
+		e.tmpl = ec.tmpl
+		e.tmplMsg = ec.tmplMsg
+		e.decoded = ec.decoded
 
 		// If the original error have Meta, copy over onto the new error
 		if len(ec.Meta) > 0 {
@@ -74,7 +158,12 @@ func M(err error, args ...any) error {
 	e.withFlag = err
 
 	// Overwrite the source to where M() was called, otherwise source will point to where err was instantiated.
-	e.Source = getSource()
+	e.Source = getSource(skip)
+
+	// Record the stack where M() was called if requested, otherwise keep the stack of the original error.
+	if captureStack(args) {
+		e.Stack = getStack(skip)
+	}
 
 	// Parse the args too
 	e.parseArgTypes(args...)
@@ -84,23 +173,95 @@ func M(err error, args ...any) error {
 
 // With is deprecated, see M. It is kept for backwards compatibility.
 func With(err error, args ...any) error {
-	return M(err, args...)
+	e := mirror(1, err, args)
+	runHooks(e)
+
+	return e
 }
 
-// GetMeta returns a Meta map or an empty Meta if the error doesn't contain a Meta or the error is not of type
-// errors.Error. The second returned argument is TRUE if the err has a Meta, FALSE otherwise.
+// GetMeta returns the Meta map of the first errors.Error in err's chain or an empty Meta if the chain doesn't contain
+// an errors.Error. The second returned argument is TRUE if an errors.Error was found, FALSE otherwise. Use LookupMeta or
+// AllMeta to read the Meta of every error in the chain.
 func GetMeta(err error) (Meta, bool) {
-	eerr, ok := err.(*Error)
+	var eerr *Error
 
-	if !ok {
+	if !As(err, &eerr) {
 		return make(Meta, 1), false
 	}
 
 	return eerr.Meta, true
 }
 
+// LookupMeta returns the value of key from the outermost error in err's chain that has it set. The second returned
+// argument is FALSE if none of the errors have the key set.
+func LookupMeta(err error, key string) (value any, found bool) {
+	Walk(err, func(wErr error, _ int) bool {
+		e, ok := wErr.(*Error)
+		if !ok {
+			return true
+		}
+
+		value, found = e.Meta[key]
+
+		return !found
+	})
+
+	return
+}
+
+// MetaPrecedence decides which value AllMeta keeps when multiple errors in the chain have the same key.
+type MetaPrecedence uint8
+
+// Meta precedences.
+const (
+	OuterFirst MetaPrecedence = iota // Values of outer errors overwrite the values of the errors they wrap.
+	InnerFirst                       // Values of wrapped errors overwrite the values of the errors wrapping them.
+)
+
+// AllMetaOptions configures AllMeta.
+type AllMetaOptions struct {
+	// Precedence decides which value is kept for keys set on multiple errors, defaults to OuterFirst.
+	Precedence MetaPrecedence
+
+	// Namespace returns the prefix of the keys of each error, the keys are set as <prefix>.<key>. index is the position
+	// of the error in Flatten. If it's nil or returns an empty string the keys are not prefixed.
+	Namespace func(index int, e Error) string
+}
+
+// AllMeta merges the Meta of every error in err's chain into a new Meta. opts can be nil.
+func AllMeta(err error, opts *AllMetaOptions) Meta {
+	var o AllMetaOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	errs := Flatten(err)
+	m := make(Meta)
+
+	for i := range errs {
+		// Merge the winning layer last so its values overwrite the others.
+		idx := len(errs) - 1 - i
+		if o.Precedence == InnerFirst {
+			idx = i
+		}
+
+		prefix := ""
+		if o.Namespace != nil {
+			if ns := o.Namespace(idx, errs[idx]); len(ns) > 0 {
+				prefix = ns + "."
+			}
+		}
+
+		for k, v := range errs[idx].Meta {
+			m[prefix+k] = v
+		}
+	}
+
+	return m
+}
+
 // MergeMeta will merge m to err.Meta if err is of type errors.Error and returns TRUE if the operation was successful,
-// FALSE otherwise.
+// FALSE otherwise. The OnCreate hooks are called with the updated error.
 func MergeMeta(err error, m Meta) (bool, error) {
 	var e *Error
 
@@ -117,41 +278,51 @@ func MergeMeta(err error, m Meta) (bool, error) {
 		e.Meta.Set(k, v)
 	}
 
+	runHooks(e)
+
 	return true, e
 }
 
-// Flatten returns a slice of Error from embedded err.
+// Flatten returns a slice of Error from embedded err. Errors with multiple causes, ex. created by passing multiple
+// errors to E or by the stdlib errors.Join, are flattened in depth-first order, see Walk.
 func Flatten(err error) (ret []Error) {
-	if err == nil {
-		return
-	}
+	Walk(err, func(wErr error, _ int) bool {
+		ret = append(ret, toError(wErr))
+		return true
+	})
+
+	return
+}
 
-	uErr := err
+// toError returns the Error value of err, a regular error is converted to Error.
+func toError(err error) Error {
+	if e, ok := err.(*Error); ok {
+		return *e
+	}
 
-	for ok := true; ok; ok = (uErr != nil) {
-		var e *Error
-		cOk := As(uErr, &e)
+	// This is a regular error, convert it to errors.Error
+	var ee Error
+	ee.Msg = err.Error()
+	ee.err = err
 
-		if cOk == true {
-			ret = append(ret, *e)
-			uErr = Unwrap(uErr)
+	return ee
+}
 
-			continue
+// Is reports whether e matches target. If target is a Kind, e matches when it has the same kind set, Other never
+// matches. If target is a Template, e matches when it was created from it.
+func (e Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Kind:
+		if t != Other && e.Kind == t {
+			return true
 		}
 
-		// This is a regular error, convert it to errors.Error
-		var ee Error
-		ee.Msg = uErr.Error()
-		ee.err = uErr
-
-		ret = append(ret, ee)
-		uErr = Unwrap(uErr)
+	case *Template:
+		if e.matchTemplate(t) {
+			return true
+		}
 	}
 
-	return
-}
-
-func (e Error) Is(target error) bool {
 	if stderrors.Is(e.withFlag, target) {
 		return true
 	}
@@ -167,7 +338,8 @@ func (e Error) As(target any) bool {
 	return stderrors.As(e.err, target)
 }
 
-// Unwrap returns the error one level deep otherwise nil. This is a proxy method for Unwrap().
+// Unwrap returns the error one level deep otherwise nil. This is a proxy method for Unwrap(). If e has multiple causes
+// the returned error groups them and implements Unwrap() []error, so the stdlib Is and As still match every cause.
 func (e *Error) Unwrap() error {
 	return e.err
 }
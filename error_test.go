@@ -2,7 +2,9 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -19,6 +21,12 @@ func TestErrors(t *testing.T) {
 	t.Run("it should merge Meta to error with existing Meta", mergeMetaToErrorExistingMeta)
 	t.Run("it should fail merge Meta on regular error", mergeMetaToRegularError)
 	t.Run("it should flatten all embedded errors", flattenAllErrors)
+	t.Run("it should get meta from a wrapped error", getMetaFromWrappedError)
+	t.Run("it should lookup meta in the whole chain", lookupMetaInChain)
+	t.Run("it should merge meta of every error", mergeAllMeta)
+	t.Run("it should merge meta with inner precedence", mergeAllMetaInnerFirst)
+	t.Run("it should namespace merged meta", mergeAllMetaNamespaced)
+	t.Run("it should not prefix keys of an empty namespace", mergeAllMetaEmptyNamespace)
 }
 
 func storeMsg(t *testing.T) {
@@ -43,7 +51,7 @@ func storeSource(t *testing.T) {
 	e := E("test error")
 	ee := e.(*Error)
 
-	if len(string(ee.Source)) == 0 {
+	if ee.Source.IsZero() {
 		t.Fatalf("E() should store the source, got empty string.")
 	}
 }
@@ -245,3 +253,83 @@ func flattenAllErrors(t *testing.T) {
 		}
 	}
 }
+
+func getMetaFromWrappedError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", E("test error", WithMeta("key1", "val1")))
+
+	m, has := GetMeta(err)
+	if has == false || m["key1"] != "val1" {
+		t.Fatalf("GetMeta() should find the wrapped error Meta, got: %+v", m)
+	}
+}
+
+func lookupMetaInChain(t *testing.T) {
+	err := E("outer", WithMeta("shared", "outer"), E("inner", WithMeta("shared", "inner", "deep", 1)))
+
+	if v, ok := LookupMeta(err, "deep"); !ok || v != 1 {
+		t.Fatalf("LookupMeta() should find the inner value, got: %+v, %+v", v, ok)
+	}
+
+	if v, _ := LookupMeta(err, "shared"); v != "outer" {
+		t.Fatalf("LookupMeta() should return the outermost value, got: %+v", v)
+	}
+
+	if _, ok := LookupMeta(err, "missing"); ok {
+		t.Fatalf("LookupMeta() should return false for a missing key")
+	}
+
+	if _, ok := LookupMeta(errors.New("regular"), "shared"); ok {
+		t.Fatalf("LookupMeta() should return false for a regular error")
+	}
+}
+
+func mergeAllMeta(t *testing.T) {
+	err := E("outer", WithMeta("shared", "outer", "a", 1), E("inner", WithMeta("shared", "inner", "b", 2)))
+
+	m := AllMeta(err, nil)
+	cmp := WithMeta("shared", "outer", "a", 1, "b", 2)
+
+	if reflect.DeepEqual(m, cmp) == false {
+		t.Fatalf("AllMeta() expected: %+v, got: %+v", cmp, m)
+	}
+
+	if len(AllMeta(nil, nil)) != 0 {
+		t.Fatalf("AllMeta() should return an empty Meta for nil")
+	}
+}
+
+func mergeAllMetaInnerFirst(t *testing.T) {
+	err := E("outer", WithMeta("shared", "outer"), E("inner", WithMeta("shared", "inner")))
+
+	if m := AllMeta(err, &AllMetaOptions{Precedence: InnerFirst}); m["shared"] != "inner" {
+		t.Fatalf("AllMeta() should keep the inner value, got: %+v", m)
+	}
+}
+
+func mergeAllMetaNamespaced(t *testing.T) {
+	err := E("outer", WithMeta("shared", "outer"), E("inner", WithMeta("shared", "inner")))
+
+	m := AllMeta(err, &AllMetaOptions{Namespace: func(i int, _ Error) string { return strconv.Itoa(i) }})
+	cmp := WithMeta("0.shared", "outer", "1.shared", "inner")
+
+	if reflect.DeepEqual(m, cmp) == false {
+		t.Fatalf("AllMeta() expected: %+v, got: %+v", cmp, m)
+	}
+}
+
+func mergeAllMetaEmptyNamespace(t *testing.T) {
+	err := E("outer", WithMeta("requestId", "r1"), E("inner", WithMeta("query", "select")))
+
+	m := AllMeta(err, &AllMetaOptions{Namespace: func(i int, e Error) string {
+		if i == 0 {
+			return ""
+		}
+
+		return e.Msg
+	}})
+	cmp := WithMeta("requestId", "r1", "inner.query", "select")
+
+	if reflect.DeepEqual(m, cmp) == false {
+		t.Fatalf("AllMeta() expected: %+v, got: %+v", cmp, m)
+	}
+}
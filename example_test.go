@@ -38,7 +38,7 @@ func ExampleUnwrap() {
 	err2 := errors.E("error 2", err1)
 
 	e := errors.Unwrap(err2)
-	fmt.Printf("%+v", e)
+	fmt.Printf("%v", e)
 
 	// Output: error 1
 }
@@ -84,3 +84,65 @@ func ExampleMeta_Merge() {
 
 	// Output: errors.Meta{"key1":"val1", "key2":"val2"}
 }
+
+func ExampleMeta_String() {
+	m := errors.WithMeta("userId", 10, "action", "login", "isAuth", true)
+
+	fmt.Println(m)
+
+	// Output: [action:login isAuth:true userId:10]
+}
+
+func ExampleKindOf() {
+	err1 := errors.E("user not found", errors.NotFound)
+	err2 := errors.E("cannot load profile", err1)
+
+	fmt.Println(errors.KindOf(err2))
+	fmt.Println(errors.Is(err2, errors.NotFound))
+
+	// Output: not_found
+	// true
+}
+
+func ExampleError_Format() {
+	err1 := errors.E("error 1")
+	err2 := errors.E("error 2", err1)
+
+	fmt.Printf("%s\n", err2)
+	fmt.Printf("%q\n", err2)
+
+	// %+v prints every error in the chain with its source and meta, same as PrettyPrint.
+	fmt.Printf("%+v\n", err2)
+}
+
+func ExamplePrettyTree() {
+	err := errors.E("cannot save user",
+		errors.E("validation failed", errors.WithMeta("field", "email")),
+		errors.E("audit log unavailable", errors.Unavailable),
+	)
+
+	fmt.Print(errors.PrettyTree(err, &errors.TreeOptions{ASCII: true}))
+}
+
+var ErrUserNotFound = errors.Define("user.not_found", "user not found", errors.NotFound)
+
+func ExampleDefine() {
+	err := errors.E("cannot load profile", ErrUserNotFound.New(errors.WithMeta("userId", 10)))
+
+	fmt.Println(errors.Is(err, ErrUserNotFound))
+
+	// Output: true
+}
+
+func ExampleError_Error() {
+	err := errors.E("user {userId} not found", errors.TemplateMsg, errors.WithMeta("userId", 10))
+
+	var e *errors.Error
+	errors.As(err, &e)
+
+	fmt.Println(err)
+	fmt.Println(e.RawMessage())
+
+	// Output: user 10 not found
+	// user {userId} not found
+}
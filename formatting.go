@@ -2,47 +2,100 @@ package errors
 
 import (
 	"fmt"
+	"io"
+	"strings"
 )
 
-// Error returns the error message and satisfies the stdlib Error interface.
+// Error returns the error message and satisfies the stdlib Error interface. If the error was created with
+// TemplateMsg the {key} placeholders of the message are replaced with the values of the error Meta, sensitive values
+// are redacted and missing keys are rendered as {!MISSING:key}, use {{ and }} for literal braces. See RawMessage for
+// the message template.
+//
+//	errors.E("user {userId} not found", errors.TemplateMsg, errors.WithMeta("userId", 10)).Error() // user 10 not found
 func (e *Error) Error() string {
 	if len(e.Msg) == 0 {
 		return "<empty>"
 	}
 
-	return e.Msg
+	return e.message()
 }
 
-// PrettyPrint is a helper method to *Error.PrettyPrint. This should only be used in development.
+// Format implements fmt.Formatter for Error.
+//
+//	%s    error message
+//	%v    error message
+//	%q    double-quoted error message
+//	%+v   every error in the chain including its kind, code, source, meta and stack, same as PrettyPrint
+//	%#v   Go-syntax representation of the error including the wrapped errors
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			_, _ = io.WriteString(s, strings.Trim(e.PrettyPrint(), "\n"))
+		case s.Flag('#'):
+			e.formatGoSyntax(s)
+		default:
+			_, _ = io.WriteString(s, e.Error())
+		}
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*errors.Error=%s)", verb, e.Error())
+	}
+}
+
+// formatGoSyntax writes e in Go-syntax, the wrapped error is written recursively in the unexported err field.
+func (e *Error) formatGoSyntax(s fmt.State) {
+	fmt.Fprintf(s, "&errors.Error{Msg:%#v, PublicMsg:%#v, Kind:%#v, Code:%#v, Source:%#v, Meta:%#v, Stack:%#v", e.Msg,
+		e.PublicMsg, e.Kind, e.Code, e.Source, e.Meta.Redacted(), e.Stack)
+
+	if e.err != nil {
+		fmt.Fprintf(s, ", err:%#v", e.err)
+	}
+
+	_, _ = io.WriteString(s, "}")
+}
+
+// PrettyPrint prints every error in err's tree the same way as *Error.PrettyPrint, including all branches of errors
+// with multiple causes, see Flatten. If the tree doesn't contain an errors.Error the message of err is returned. This
+// should only be used in development.
 func PrettyPrint(err error) string {
 	var e *Error
-	is := As(err, &e)
-
-	if is == false {
+	if !As(err, &e) {
 		return err.Error()
 	}
 
-	return e.PrettyPrint()
+	return prettyPrint(Flatten(err))
 }
 
 // PrettyPrint will recursively print all embedded errors including all information on the error it can found.
 // This should only be used in development.
 func (e *Error) PrettyPrint() string {
-	err := Flatten(e)
+	return prettyPrint(Flatten(e))
+}
 
+// prettyPrint renders the flattened errors for PrettyPrint.
+func prettyPrint(err []Error) string {
 	var b []byte
 
 	b = append(b, '\n')
 
 	for i, elem := range err {
-		if len(e.Msg) == 0 {
+		if len(elem.Msg) == 0 {
 			b = append(b, "<empty>"...)
 		} else {
-			b = append(b, elem.Msg...)
+			b = append(b, elem.message()...)
 		}
 
+		b = append(b, publicPrettyString(elem.PublicMsg)...)
+		b = append(b, elem.Kind.kindPrettyString()...)
+		b = append(b, elem.Code.codePrettyString()...)
 		b = append(b, elem.Source.sourcePrettyString()...)
 		b = append(b, elem.Meta.metaPrettyString()...)
+		b = append(b, elem.Stack.stackPrettyString()...)
 
 		if i < len(err) {
 			b = append(b, '\n')
@@ -54,16 +107,16 @@ func (e *Error) PrettyPrint() string {
 
 // String returns Meta in [key1:val1 key2:val2 ...] format and satisfies the fmt.Stringer interface.
 func (p Meta) String() string {
+	p = p.Redacted()
+
 	var b []byte
 
 	b = append(b, '[')
 
-	i := 0
-	for k, v := range p {
-		b = fmt.Appendf(b, "%s:%+v", k, v)
+	for i, k := range p.Keys() {
+		b = fmt.Appendf(b, "%s:%+v", k, p[k])
 
-		i++
-		if i < len(p) {
+		if i < len(p)-1 {
 			b = append(b, ' ')
 		}
 	}
@@ -73,13 +126,51 @@ func (p Meta) String() string {
 	return string(b)
 }
 
+// GoString returns Meta in Go-syntax with the sensitive values redacted and satisfies the fmt.GoStringer interface.
+func (p Meta) GoString() string {
+	return "errors.Meta" + strings.TrimPrefix(fmt.Sprintf("%#v", map[string]any(p.Redacted())), "map[string]interface {}")
+}
+
+func (k Kind) kindPrettyString() string {
+	if k == Other {
+		return ""
+	}
+
+	var b []byte
+	b = fmt.Appendf(b, "\n%*s|- Kind : %s", 2, " ", k)
+
+	return string(b)
+}
+
+func publicPrettyString(msg string) string {
+	if len(msg) == 0 {
+		return ""
+	}
+
+	var b []byte
+	b = fmt.Appendf(b, "\n%*s|- Public : %s", 2, " ", msg)
+
+	return string(b)
+}
+
+func (c Code) codePrettyString() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	var b []byte
+	b = fmt.Appendf(b, "\n%*s|- Code : %s", 2, " ", string(c))
+
+	return string(b)
+}
+
 func (p *Source) sourcePrettyString() string {
-	if len(string(*p)) == 0 {
+	if p.IsZero() {
 		return ""
 	}
 
 	var b []byte
-	b = fmt.Appendf(b, "\n%*s|- Source : %s", 2, " ", string(*p))
+	b = fmt.Appendf(b, "\n%*s|- Source : %s", 2, " ", p.String())
 
 	return string(b)
 }
@@ -89,13 +180,31 @@ func (p Meta) metaPrettyString() string {
 		return ""
 	}
 
+	p = p.Redacted()
+
 	var b []byte
 
 	b = fmt.Appendf(b, "\n%*s|- Meta :", 2, " ")
 
-	for k := range p {
+	for _, k := range p.Keys() {
 		b = fmt.Appendf(b, "\n%*s|- %s : %+v", 4, " ", k, p[k])
 	}
 
 	return string(b)
 }
+
+func (st StackTrace) stackPrettyString() string {
+	if len(st) == 0 {
+		return ""
+	}
+
+	var b []byte
+
+	b = fmt.Appendf(b, "\n%*s|- Stack :", 2, " ")
+
+	for _, f := range st {
+		b = fmt.Appendf(b, "\n%*s|- %s\n%*s%s:%d", 4, " ", f.name(), 8, " ", f.file(), f.line())
+	}
+
+	return string(b)
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatting(t *testing.T) {
+	t.Run("it should print the message with %s and %v", formatMessage)
+	t.Run("it should quote the message with %q", formatQuoted)
+	t.Run("it should print the chain with %+v", formatChain)
+	t.Run("it should print Go-syntax with %#v", formatGoSyntax)
+}
+
+func formatMessage(t *testing.T) {
+	err := E("error 2", E("error 1"))
+
+	if s := fmt.Sprintf("%s", err); s != "error 2" {
+		t.Fatalf("expected %%s to print the message, got: %s", s)
+	}
+
+	if s := fmt.Sprintf("%v", err); s != "error 2" {
+		t.Fatalf("expected %%v to print the message, got: %s", s)
+	}
+
+	if s := fmt.Sprintf("wrapped: %v", fmt.Errorf("ctx: %w", err)); s != "wrapped: ctx: error 2" {
+		t.Fatalf("expected %%v to work when wrapped, got: %s", s)
+	}
+}
+
+func formatQuoted(t *testing.T) {
+	if s := fmt.Sprintf("%q", E(`say "hi"`)); s != `"say \"hi\""` {
+		t.Fatalf("expected %%q to quote the message, got: %s", s)
+	}
+}
+
+func formatChain(t *testing.T) {
+	err := E("error 2", E("error 1", WithMeta("key", "val")), NotFound)
+
+	s := fmt.Sprintf("%+v", err)
+
+	for _, want := range []string{"error 2", "error 1", "|- Kind : not_found", "|- Source : ", "formatting_test.go", "|- key : val"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("expected %%+v output to contain %q, got: %s", want, s)
+		}
+	}
+
+	if strings.HasPrefix(s, "\n") || strings.HasSuffix(s, "\n") {
+		t.Fatalf("expected %%+v output without surrounding new lines, got: %q", s)
+	}
+}
+
+func formatGoSyntax(t *testing.T) {
+	err := E("error 2", E("error 1", errors.New("regular")), WithMeta("key", "val"))
+
+	s := fmt.Sprintf("%#v", err)
+
+	for _, want := range []string{`&errors.Error{Msg:"error 2"`, `Meta:errors.Meta{"key":"val"}`, `err:&errors.Error{Msg:"error 1"`, `err:&errors.errorString{s:"regular"}`} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("expected %%#v output to contain %q, got: %s", want, s)
+		}
+	}
+}
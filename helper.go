@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// helpers holds the functions and packages whose frames are skipped when attributing the Source of an error.
+var helpers = struct {
+	mu     sync.RWMutex
+	active atomic.Bool
+	funcs  map[string]struct{}
+	pkgs   map[string]struct{}
+}{}
+
+// Helper marks the calling function as an error helper, similar to testing.T.Helper. When an error is created by E or M
+// the frames of helper functions are skipped, so Source and Stack point to the caller of the helper. Helper can be
+// called on every invocation, registering a function more than once has no effect.
+//
+//	func notFound(what string) error {
+//		errors.Helper()
+//		return errors.E(what+" not found", errors.NotFound)
+//	}
+func Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return
+	}
+
+	registerHelper(fn.Name())
+}
+
+// RegisterHelperFunc marks fn as an error helper, see Helper. fn must be a function, other values are ignored.
+func RegisterHelperFunc(fn any) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return
+	}
+
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		registerHelper(f.Name())
+	}
+}
+
+// RegisterHelperPackage marks every function of the package with the import path pkgPath as an error helper, see
+// Helper. Useful for packages wrapping E and M, like a logging or a database package.
+func RegisterHelperPackage(pkgPath string) {
+	helpers.mu.Lock()
+	defer helpers.mu.Unlock()
+
+	if helpers.pkgs == nil {
+		helpers.pkgs = make(map[string]struct{})
+	}
+
+	helpers.pkgs[pkgPath] = struct{}{}
+	helpers.active.Store(true)
+}
+
+// registerHelper adds the fully qualified function name to the helpers.
+func registerHelper(name string) {
+	helpers.mu.RLock()
+	_, has := helpers.funcs[name]
+	helpers.mu.RUnlock()
+
+	if has {
+		return
+	}
+
+	helpers.mu.Lock()
+	defer helpers.mu.Unlock()
+
+	if helpers.funcs == nil {
+		helpers.funcs = make(map[string]struct{})
+	}
+
+	helpers.funcs[name] = struct{}{}
+	helpers.active.Store(true)
+}
+
+// isHelper reports whether the frames of the fully qualified function name must be skipped.
+func isHelper(name string) bool {
+	if !helpers.active.Load() {
+		return false
+	}
+
+	helpers.mu.RLock()
+	defer helpers.mu.RUnlock()
+
+	if _, has := helpers.funcs[name]; has {
+		return true
+	}
+
+	_, has := helpers.pkgs[packagePath(name)]
+
+	return has
+}
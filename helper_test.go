@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestHelper(t *testing.T) {
+	t.Run("it should skip frames with ESkip", skipFramesESkip)
+	t.Run("it should skip frames with MSkip", skipFramesMSkip)
+	t.Run("it should skip functions marked with Helper", skipMarkedHelper)
+	t.Run("it should skip registered functions", skipRegisteredFunc)
+	t.Run("it should skip registered packages", skipRegisteredPackage)
+	t.Run("it should attribute With to its caller", attributeWithToCaller)
+}
+
+func newSkipError() error {
+	return ESkip(1, "skip error", WithStack)
+}
+
+func markedHelper() error {
+	Helper()
+	return E("helper error", WithStack)
+}
+
+func registeredHelper() error {
+	return M(E("inner"), WithStack)
+}
+
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func skipFramesESkip(t *testing.T) {
+	err, line := newSkipError(), callerLine()
+	e := err.(*Error)
+
+	if e.Source.Line() != line || e.Source.Function() != "github.com/primalskill/errors.skipFramesESkip" {
+		t.Fatalf("expected the source to be the caller of the helper, got: %s %s", e.Source, e.Source.Function())
+	}
+
+	if e.Stack[0].line() != line {
+		t.Fatalf("expected the stack to start at the caller of the helper, got line: %d", e.Stack[0].line())
+	}
+}
+
+func skipFramesMSkip(t *testing.T) {
+	wrap := func(err error) error {
+		return MSkip(1, err)
+	}
+
+	err, line := wrap(E("inner")), callerLine()
+	e := err.(*Error)
+
+	if e.Source.Line() != line {
+		t.Fatalf("expected the source line %d, got: %s", line, e.Source)
+	}
+}
+
+func skipMarkedHelper(t *testing.T) {
+	err, line := markedHelper(), callerLine()
+	e := err.(*Error)
+
+	if e.Source.Line() != line {
+		t.Fatalf("expected the source line %d, got: %s", line, e.Source)
+	}
+
+	if e.Stack[0].line() != line {
+		t.Fatalf("expected the stack to start at the caller of the helper, got line: %d", e.Stack[0].line())
+	}
+}
+
+func skipRegisteredFunc(t *testing.T) {
+	RegisterHelperFunc(registeredHelper)
+	defer removeHelpers()
+
+	err, line := registeredHelper(), callerLine()
+	e := err.(*Error)
+
+	if e.Source.Line() != line {
+		t.Fatalf("expected the source line %d, got: %s", line, e.Source)
+	}
+}
+
+func skipRegisteredPackage(t *testing.T) {
+	RegisterHelperPackage("github.com/primalskill/errors")
+	defer removeHelpers()
+
+	e := E("test").(*Error)
+
+	if e.Source.Package() != "testing" {
+		t.Fatalf("expected every frame of the package to be skipped, got: %s", e.Source.Function())
+	}
+}
+
+func attributeWithToCaller(t *testing.T) {
+	err, line := With(E("inner")), callerLine()
+	e := err.(*Error)
+
+	if e.Source.Line() != line {
+		t.Fatalf("expected the source line %d, got: %s", line, e.Source)
+	}
+}
+
+func removeHelpers() {
+	helpers.mu.Lock()
+	helpers.funcs = nil
+	helpers.pkgs = nil
+	helpers.active.Store(false)
+	helpers.mu.Unlock()
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// HookFilter selects the errors passed to a hook registered with OnCreate.
+type HookFilter func(e *Error) bool
+
+// hook is a registered OnCreate function with its filters.
+type hook struct {
+	fn      func(*Error)
+	filters []HookFilter
+}
+
+// hooks holds the registered hooks. The slice is replaced on every change so the hooks can be called without locking.
+var hooks = struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]*hook]
+}{}
+
+// OnCreate registers fn to be called with every error created by E, M and Template.New, and with the updated error
+// every time MergeMeta changes its Meta. Useful to feed metrics, tracing span events or debug logging from one place.
+// If filters are passed fn is only called for errors matching all of them, see FilterKind and FilterMeta.
+//
+// Hooks run synchronously on the goroutine creating the error, in the order they were registered, so they must be
+// fast and must not modify the error. OnCreate is safe for concurrent use, the returned function removes the hook.
+func OnCreate(fn func(*Error), filters ...HookFilter) (remove func()) {
+	h := &hook{fn: fn, filters: filters}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	var list []*hook
+	if current := hooks.list.Load(); current != nil {
+		list = append(list, *current...)
+	}
+
+	list = append(list, h)
+	hooks.list.Store(&list)
+
+	return func() {
+		removeHook(h)
+	}
+}
+
+// FilterKind returns a HookFilter matching the errors with one of the kinds.
+func FilterKind(kinds ...Kind) HookFilter {
+	return func(e *Error) bool {
+		for _, k := range kinds {
+			if e.Kind == k {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// FilterMeta returns a HookFilter matching the errors having key in their Meta. If values are passed the value of the
+// key must also be equal to one of them.
+func FilterMeta(key string, values ...any) HookFilter {
+	return func(e *Error) bool {
+		v, has := e.Meta[key]
+		if !has {
+			return false
+		}
+
+		if len(values) == 0 {
+			return true
+		}
+
+		for _, want := range values {
+			if reflect.DeepEqual(v, want) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// removeHook removes h from the registered hooks.
+func removeHook(h *hook) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	current := hooks.list.Load()
+	if current == nil {
+		return
+	}
+
+	list := make([]*hook, 0, len(*current))
+	for _, ch := range *current {
+		if ch != h {
+			list = append(list, ch)
+		}
+	}
+
+	hooks.list.Store(&list)
+}
+
+// runHooks calls the registered hooks matching e.
+func runHooks(e *Error) {
+	list := hooks.list.Load()
+	if list == nil {
+		return
+	}
+
+	for _, h := range *list {
+		if h.match(e) {
+			h.fn(e)
+		}
+	}
+}
+
+// match reports whether e matches all the filters of h.
+func (h *hook) match(e *Error) bool {
+	for _, f := range h.filters {
+		if !f(e) {
+			return false
+		}
+	}
+
+	return true
+}
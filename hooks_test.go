@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestOnCreate(t *testing.T) {
+	t.Run("it should call the hooks from E, M and New", callHooksOnCreate)
+	t.Run("it should call the hooks from MergeMeta", callHooksOnMergeMeta)
+	t.Run("it should filter by kind", filterHooksByKind)
+	t.Run("it should filter by meta", filterHooksByMeta)
+	t.Run("it should remove the hook", removeCreateHook)
+	t.Run("it should register hooks concurrently", registerHooksConcurrently)
+}
+
+func callHooksOnCreate(t *testing.T) {
+	var msgs []string
+
+	remove := OnCreate(func(e *Error) {
+		msgs = append(msgs, e.Msg)
+	})
+	defer remove()
+
+	_ = M(E("e"), WithMeta("key", "val"))
+	_ = M(errors.New("std"))
+	_ = errTestNotFound.New()
+
+	want := []string{"e", "e", "std", errTestNotFound.Error()}
+	if len(msgs) != len(want) {
+		t.Fatalf("expected %d calls, got: %+v", len(want), msgs)
+	}
+
+	for i := range want {
+		if msgs[i] != want[i] {
+			t.Fatalf("expected message %s at %d, got: %s", want[i], i, msgs[i])
+		}
+	}
+}
+
+func callHooksOnMergeMeta(t *testing.T) {
+	err := E("test")
+
+	var got Meta
+
+	remove := OnCreate(func(e *Error) {
+		got = e.Meta
+	})
+	defer remove()
+
+	_, _ = MergeMeta(err, WithMeta("key", "val"))
+
+	if got["key"] != "val" {
+		t.Fatalf("expected the hook to see the merged meta, got: %+v", got)
+	}
+}
+
+func filterHooksByKind(t *testing.T) {
+	calls := 0
+
+	remove := OnCreate(func(*Error) { calls++ }, FilterKind(NotFound, Conflict))
+	defer remove()
+
+	_ = E("not found", NotFound)
+	_ = E("conflict", Conflict)
+	_ = E("invalid", Invalid)
+	_ = E("other")
+
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got: %d", calls)
+	}
+}
+
+func filterHooksByMeta(t *testing.T) {
+	calls := 0
+
+	remove := OnCreate(func(*Error) { calls++ }, FilterMeta("tenant", "acme"), FilterKind(Internal))
+	defer remove()
+
+	_ = E("match", Internal, WithMeta("tenant", "acme"))
+	_ = E("other tenant", Internal, WithMeta("tenant", "other"))
+	_ = E("other kind", Invalid, WithMeta("tenant", "acme"))
+	_ = E("no meta", Internal)
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+func removeCreateHook(t *testing.T) {
+	calls := 0
+
+	remove := OnCreate(func(*Error) { calls++ })
+	_ = E("test")
+	remove()
+	_ = E("test")
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+func registerHooksConcurrently(t *testing.T) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		calls int
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			remove := OnCreate(func(*Error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+			})
+
+			_ = E("test")
+			remove()
+		}()
+	}
+
+	wg.Wait()
+
+	if calls < 10 {
+		t.Fatalf("expected at least 10 calls, got: %d", calls)
+	}
+
+	if list := hooks.list.Load(); list != nil && len(*list) > 0 {
+		t.Fatalf("expected every hook to be removed, got: %d", len(*list))
+	}
+}
@@ -0,0 +1,3 @@
+// Package httperr translates errors.Error values to HTTP responses by mapping error kinds, codes or a Meta key to HTTP
+// status codes.
+package httperr
@@ -0,0 +1,156 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/primalskill/errors"
+)
+
+// DefaultMetaKey is the Meta key used by NewMapper to read an explicit HTTP status code from the error chain.
+const DefaultMetaKey = "httpStatus"
+
+// Mapper maps errors to HTTP status codes. The status is resolved in the following order: the outermost MetaKey value
+// in the error chain, the outermost Code found in Codes, the outermost Kind found in Kinds and finally Default.
+type Mapper struct {
+	Kinds   map[errors.Kind]int
+	Codes   map[errors.Code]int
+	MetaKey string
+	Default int
+}
+
+// DefaultMapper is the Mapper used by the package level Status and WriteError functions.
+var DefaultMapper = NewMapper()
+
+// NewMapper returns a Mapper with the default kind table, DefaultMetaKey as the meta key and 500 Internal Server Error
+// as the default status.
+func NewMapper() *Mapper {
+	return &Mapper{
+		Kinds: map[errors.Kind]int{
+			errors.Invalid:         http.StatusBadRequest,
+			errors.Unauthenticated: http.StatusUnauthorized,
+			errors.Permission:      http.StatusForbidden,
+			errors.NotFound:        http.StatusNotFound,
+			errors.Conflict:        http.StatusConflict,
+			errors.Internal:        http.StatusInternalServerError,
+			errors.Unavailable:     http.StatusServiceUnavailable,
+			errors.Timeout:         http.StatusGatewayTimeout,
+		},
+		Codes:   make(map[errors.Code]int),
+		MetaKey: DefaultMetaKey,
+		Default: http.StatusInternalServerError,
+	}
+}
+
+// Status returns the HTTP status code for err using DefaultMapper.
+func Status(err error) int {
+	return DefaultMapper.Status(err)
+}
+
+// WriteError writes err to w using DefaultMapper, see Mapper.WriteError.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultMapper.WriteError(w, r, err)
+}
+
+// Status returns the HTTP status code for err. A nil err returns 200 OK.
+func (m *Mapper) Status(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	errs := errors.Flatten(err)
+
+	if len(m.MetaKey) > 0 {
+		for _, e := range errs {
+			if status, ok := metaStatus(e.Meta, m.MetaKey); ok {
+				return status
+			}
+		}
+	}
+
+	for _, e := range errs {
+		if status, ok := m.Codes[e.Code]; ok && len(e.Code) > 0 {
+			return status
+		}
+	}
+
+	for _, e := range errs {
+		if status, ok := m.Kinds[e.Kind]; ok && e.Kind != errors.Other {
+			return status
+		}
+	}
+
+	if m.Default == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return m.Default
+}
+
+// WriteError writes err to w as a JSON response with the status code returned by Status. The body has the same shape
+// as errors.Error MarshalJSON. If the chain has a public message, see errors.Public, only the public rendering of
+// errors.MarshalJSONFor is written. For 5xx status codes without a public message the chain is hidden and only the
+// status text is rendered so internal messages don't leak to clients. The body is omitted for HEAD requests.
+// WriteError does nothing if err is nil.
+func (m *Mapper) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	status := m.Status(err)
+
+	var (
+		b    []byte
+		merr error
+	)
+
+	if _, ok := errors.LookupPublicMessage(err); ok {
+		b, merr = errors.MarshalJSONFor(err, errors.AudiencePublic)
+	} else if status >= http.StatusInternalServerError {
+		b, merr = json.Marshal(&errors.Error{Msg: http.StatusText(status)})
+	} else if _, ok := err.(*errors.Error); ok {
+		b, merr = json.Marshal(err)
+	} else {
+		// Convert regular errors so they are rendered with the errors.Error JSON shape.
+		b, merr = json.Marshal(&errors.Error{Msg: err.Error()})
+	}
+
+	if merr != nil {
+		status = http.StatusInternalServerError
+		b, _ = json.Marshal(&errors.Error{Msg: http.StatusText(status)})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	if r != nil && r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(b)
+}
+
+// metaStatus returns the HTTP status code stored under key in m. Numeric values and numeric strings are accepted, values
+// outside of the 100-599 range are ignored.
+func metaStatus(m errors.Meta, key string) (int, bool) {
+	status, ok := m.GetInt(key)
+	if !ok {
+		s, isStr := m.GetString(key)
+		if !isStr {
+			return 0, false
+		}
+
+		var err error
+		if status, err = strconv.Atoi(s); err != nil {
+			return 0, false
+		}
+	}
+
+	if status < 100 || status > 599 {
+		return 0, false
+	}
+
+	return status, true
+}
@@ -0,0 +1,208 @@
+package httperr
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primalskill/errors"
+)
+
+func TestHTTPErr(t *testing.T) {
+	t.Run("it should map kinds to status codes", mapKindsToStatus)
+	t.Run("it should map codes to status codes", mapCodesToStatus)
+	t.Run("it should use the outermost mapped code", useOutermostMappedCode)
+	t.Run("it should use the outermost mapped kind", useOutermostMappedKind)
+	t.Run("it should prefer the status from meta", preferMetaStatus)
+	t.Run("it should fall back to the default status", fallbackDefaultStatus)
+	t.Run("it should write the error chain for 4xx", writeErrorChain4xx)
+	t.Run("it should hide internal messages for 5xx", hideInternalMessages5xx)
+	t.Run("it should write the public message for 5xx", writePublicMessage5xx)
+	t.Run("it should write only the public message for 4xx", writePublicMessage4xx)
+	t.Run("it should omit the body for HEAD requests", omitBodyHead)
+}
+
+func mapKindsToStatus(t *testing.T) {
+	cases := map[errors.Kind]int{
+		errors.Invalid:     http.StatusBadRequest,
+		errors.NotFound:    http.StatusNotFound,
+		errors.Permission:  http.StatusForbidden,
+		errors.Unavailable: http.StatusServiceUnavailable,
+	}
+
+	for k, status := range cases {
+		err := errors.E("wrapper", errors.E("test", k))
+
+		if got := Status(err); got != status {
+			t.Fatalf("Status() for kind %s expected: %d, got: %d", k, status, got)
+		}
+	}
+}
+
+func mapCodesToStatus(t *testing.T) {
+	m := NewMapper()
+	m.Codes["payment.required"] = http.StatusPaymentRequired
+
+	err := errors.E("test", errors.Invalid, errors.Code("payment.required"))
+
+	if got := m.Status(err); got != http.StatusPaymentRequired {
+		t.Fatalf("Status() expected: %d, got: %d", http.StatusPaymentRequired, got)
+	}
+}
+
+func useOutermostMappedCode(t *testing.T) {
+	m := NewMapper()
+	m.Codes["inner"] = http.StatusConflict
+
+	err := errors.E("outer", errors.Code("outer"), errors.E("inner", errors.Code("inner")))
+
+	if got := m.Status(err); got != http.StatusConflict {
+		t.Fatalf("Status() expected: %d, got: %d", http.StatusConflict, got)
+	}
+}
+
+func useOutermostMappedKind(t *testing.T) {
+	m := NewMapper()
+	delete(m.Kinds, errors.Invalid)
+
+	err := errors.E("outer", errors.Invalid, errors.E("inner", errors.NotFound))
+
+	if got := m.Status(err); got != http.StatusNotFound {
+		t.Fatalf("Status() expected: %d, got: %d", http.StatusNotFound, got)
+	}
+}
+
+func preferMetaStatus(t *testing.T) {
+	err := errors.E("test", errors.NotFound, errors.WithMeta(DefaultMetaKey, http.StatusGone))
+
+	if got := Status(err); got != http.StatusGone {
+		t.Fatalf("Status() expected: %d, got: %d", http.StatusGone, got)
+	}
+
+	err = errors.E("test", errors.NotFound, errors.WithMeta(DefaultMetaKey, "not a status"))
+
+	if got := Status(err); got != http.StatusNotFound {
+		t.Fatalf("Status() should ignore invalid meta values, expected: %d, got: %d", http.StatusNotFound, got)
+	}
+}
+
+func fallbackDefaultStatus(t *testing.T) {
+	if got := Status(stderrors.New("regular error")); got != http.StatusInternalServerError {
+		t.Fatalf("Status() expected: %d, got: %d", http.StatusInternalServerError, got)
+	}
+
+	if got := Status(nil); got != http.StatusOK {
+		t.Fatalf("Status() on nil expected: %d, got: %d", http.StatusOK, got)
+	}
+}
+
+func writeErrorChain4xx(t *testing.T) {
+	err := errors.E("invalid request", errors.E("email is required", errors.Invalid))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+
+	WriteError(w, r, err)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got: %d", http.StatusBadRequest, res.StatusCode)
+	}
+
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("expected json content type, got: %s", ct)
+	}
+
+	var body []errors.Error
+	if derr := json.NewDecoder(res.Body).Decode(&body); derr != nil {
+		t.Fatalf("expected json body, got error: %s", derr.Error())
+	}
+
+	if len(body) != 2 || body[1].Msg != "email is required" {
+		t.Fatalf("expected the error chain in the body, got: %+v", body)
+	}
+}
+
+func hideInternalMessages5xx(t *testing.T) {
+	err := errors.E("select * from users failed", errors.Internal)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	WriteError(w, r, err)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got: %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if strings.Contains(w.Body.String(), "select") {
+		t.Fatalf("expected internal message to be hidden, got: %s", w.Body.String())
+	}
+
+	var body errors.Error
+	if derr := json.Unmarshal(w.Body.Bytes(), &body); derr != nil {
+		t.Fatalf("expected json body, got error: %s", derr.Error())
+	}
+
+	if body.Msg != http.StatusText(http.StatusInternalServerError) {
+		t.Fatalf("expected status text as message, got: %s", body.Msg)
+	}
+}
+
+func omitBodyHead(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/users/1", nil)
+
+	WriteError(w, r, errors.E("not found", errors.NotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got: %d", http.StatusNotFound, w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got: %s", w.Body.String())
+	}
+}
+
+func writePublicMessage5xx(t *testing.T) {
+	err := errors.E("cannot charge card", errors.Public("payment failed, try again later"),
+		errors.E("dial tcp 10.0.0.1:443: connection refused", errors.Unavailable))
+
+	w := httptest.NewRecorder()
+	WriteError(w, httptest.NewRequest(http.MethodPost, "/payments", nil), err)
+
+	var body errors.Error
+	if derr := json.Unmarshal(w.Body.Bytes(), &body); derr != nil {
+		t.Fatalf("expected json body, got error: %s", derr.Error())
+	}
+
+	if body.Msg != "payment failed, try again later" || strings.Contains(w.Body.String(), "10.0.0.1") {
+		t.Fatalf("expected only the public message, got: %s", w.Body.String())
+	}
+}
+
+func writePublicMessage4xx(t *testing.T) {
+	err := errors.E("insert user: duplicate key", errors.Conflict, errors.Public("the email is already used"),
+		errors.WithMeta("table", "users"))
+
+	w := httptest.NewRecorder()
+	WriteError(w, httptest.NewRequest(http.MethodPost, "/users", nil), err)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got: %d", http.StatusConflict, w.Code)
+	}
+
+	var body map[string]any
+	if derr := json.Unmarshal(w.Body.Bytes(), &body); derr != nil {
+		t.Fatalf("expected json body, got error: %s", derr.Error())
+	}
+
+	if body["msg"] != "the email is already used" || body["kind"] != "conflict" || len(body) != 2 {
+		t.Fatalf("expected only the public rendering of the error, got: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,275 @@
+package httperr
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/primalskill/errors"
+)
+
+// ProblemContentType is the media type of a problem details document as defined by RFC 9457.
+const ProblemContentType = "application/problem+json"
+
+// Meta keys used to set the standard problem members from the error chain.
+const (
+	MetaKeyType     = "type"
+	MetaKeyTitle    = "title"
+	MetaKeyInstance = "instance"
+)
+
+// Extension members set on every problem built from an error.
+const (
+	ExtensionKind   = "kind"
+	ExtensionCode   = "code"
+	ExtensionErrors = "errors"
+)
+
+// Problem is a problem details document as defined by RFC 9457 (formerly RFC 7807). Extensions holds the extension
+// members and is encoded at the top level of the document next to the standard members.
+type Problem struct {
+	Type       string         `json:"type,omitempty"`
+	Title      string         `json:"title,omitempty"`
+	Status     int            `json:"status,omitempty"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// problemMembers is used to encode and decode the standard members without calling Problem.MarshalJSON recursively.
+type problemMembers Problem
+
+var standardMembers = map[string]struct{}{
+	"type":     {},
+	"title":    {},
+	"status":   {},
+	"detail":   {},
+	"instance": {},
+}
+
+// NewProblem returns the Problem for err using DefaultMapper.
+func NewProblem(err error) Problem {
+	return DefaultMapper.Problem(err)
+}
+
+// WriteProblem writes err to w as a problem details document using DefaultMapper, see Mapper.WriteProblem.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultMapper.WriteProblem(w, r, err)
+}
+
+// Problem builds a Problem from err. The status is resolved by Status, the type, title and instance members are taken
+// from the outermost MetaKeyType, MetaKeyTitle and MetaKeyInstance meta values, defaulting to "about:blank" and the
+// status text. The detail is the outermost public message of the chain, see errors.Public, or the outermost error
+// message.
+//
+// The meta of every error in the chain is added as extension members, outer errors overwrite the keys of inner ones.
+// The kind and code are added as the "kind" and "code" members and the chain as the "errors" member, encoded the same
+// way as errors.Error MarshalJSON.
+//
+// If the chain has a public message, see errors.Public, or for 5xx status codes the meta and the chain are left out so
+// internal details don't leak to clients, only the public message, the kind and the code are set. For 5xx status codes
+// without a public message the detail is empty.
+func (m *Mapper) Problem(err error) Problem {
+	status := m.Status(err)
+
+	p := Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Extensions: make(map[string]any),
+	}
+
+	if err == nil {
+		return p
+	}
+
+	errs := errors.Flatten(err)
+	publicMsg, hasPublicMsg := errors.LookupPublicMessage(err)
+	hideDetails := status >= http.StatusInternalServerError || hasPublicMsg
+
+	// Walk the chain innermost first so outer meta values take precedence.
+	for i := len(errs) - 1; i >= 0; i-- {
+		for k, v := range errs[i].Meta.Redacted() {
+			switch k {
+			case MetaKeyType:
+				setString(&p.Type, v)
+			case MetaKeyTitle:
+				setString(&p.Title, v)
+			case MetaKeyInstance:
+				setString(&p.Instance, v)
+			case m.MetaKey:
+			default:
+				if _, std := standardMembers[k]; !std && !hideDetails {
+					p.Extensions[k] = v
+				}
+			}
+		}
+	}
+
+	if kind := errors.KindOf(err); kind != errors.Other {
+		p.Extensions[ExtensionKind] = kind.String()
+	}
+
+	if code := errors.CodeOf(err); len(code) > 0 {
+		p.Extensions[ExtensionCode] = string(code)
+	}
+
+	if hideDetails {
+		p.Detail = publicMsg
+		return p
+	}
+
+	p.Detail = err.Error()
+
+	// Encode the chain the same way as errors.Error MarshalJSON, so the resolved messages and the branches are kept.
+	chain, merr := errors.MarshalJSONFor(err, errors.AudienceInternal)
+	if merr != nil {
+		return p
+	}
+
+	p.Extensions[ExtensionErrors] = json.RawMessage(chain)
+
+	return p
+}
+
+// WriteProblem writes err to w as an application/problem+json document built by Problem. If the problem doesn't
+// have an instance it is set to the request URI. The body is omitted for HEAD requests. WriteProblem does nothing if
+// err is nil.
+func (m *Mapper) WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	p := m.Problem(err)
+	if len(p.Instance) == 0 && r != nil && r.URL != nil {
+		p.Instance = r.URL.RequestURI()
+	}
+
+	b, merr := json.Marshal(p)
+	if merr != nil {
+		p = Problem{Type: "about:blank", Title: http.StatusText(http.StatusInternalServerError), Status: http.StatusInternalServerError}
+		b, _ = json.Marshal(p)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	if r != nil && r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(b)
+}
+
+// DecodeProblem reads a problem details document from r and returns it as an error chain, see Problem.Err. The second
+// returned error is not nil if the document can't be decoded.
+func DecodeProblem(r io.Reader) (error, error) {
+	var p Problem
+
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return p.Err(), nil
+}
+
+// Err converts p back to an errors.Error chain. If p carries the "errors" extension the chain is rebuilt from it,
+// otherwise a single error is returned with the detail (or the title) as message and the remaining extensions as Meta.
+// The status is stored in the DefaultMetaKey meta on the outermost error so Status returns the same status code.
+func (p Problem) Err() error {
+	var err error
+
+	if raw, has := p.Extensions[ExtensionErrors]; has {
+		b, merr := json.Marshal(raw)
+		if merr == nil {
+			err, _ = errors.FromJSON(b)
+		}
+	}
+
+	if err == nil {
+		msg := p.Detail
+		if len(msg) == 0 {
+			msg = p.Title
+		}
+
+		e := &errors.Error{Msg: msg}
+		e.Meta = make(errors.Meta, len(p.Extensions)+1)
+
+		for k, v := range p.Extensions {
+			switch k {
+			case ExtensionKind:
+				s, _ := v.(string)
+				_ = e.Kind.UnmarshalText([]byte(s))
+			case ExtensionCode:
+				s, _ := v.(string)
+				e.Code = errors.Code(s)
+			default:
+				e.Meta[k] = v
+			}
+		}
+
+		err = e
+	}
+
+	if p.Status > 0 {
+		_, _ = errors.MergeMeta(err, errors.WithMeta(DefaultMetaKey, p.Status))
+	}
+
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, the extension members are encoded next to the standard members. Extensions
+// can't overwrite the standard members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(problemMembers(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return b, err
+	}
+
+	members := make(map[string]any, len(p.Extensions)+len(standardMembers))
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+
+	var std map[string]any
+	if err = json.Unmarshal(b, &std); err != nil {
+		return nil, err
+	}
+
+	for k, v := range std {
+		members[k] = v
+	}
+
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, every member that's not a standard member is decoded into Extensions.
+func (p *Problem) UnmarshalJSON(b []byte) error {
+	var std problemMembers
+	if err := json.Unmarshal(b, &std); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+
+	*p = Problem(std)
+	p.Extensions = make(map[string]any, len(members))
+
+	for k, v := range members {
+		if _, is := standardMembers[k]; !is {
+			p.Extensions[k] = v
+		}
+	}
+
+	return nil
+}
+
+func setString(dst *string, v any) {
+	if s, ok := v.(string); ok && len(s) > 0 {
+		*dst = s
+	}
+}
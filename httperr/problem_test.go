@@ -0,0 +1,248 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primalskill/errors"
+)
+
+func TestProblem(t *testing.T) {
+	t.Run("it should build a problem from the error chain", buildProblem)
+	t.Run("it should hide internal details for 5xx", hideProblemDetails5xx)
+	t.Run("it should encode extensions at the top level", encodeProblemExtensions)
+	t.Run("it should write application/problem+json", writeProblem)
+	t.Run("it should decode a problem into an error chain", decodeProblemChain)
+	t.Run("it should decode a foreign problem into an error", decodeForeignProblem)
+	t.Run("it should redact sensitive meta", redactProblemMeta)
+	t.Run("it should use the public message as detail", publicProblemDetail)
+	t.Run("it should encode the resolved message templates", resolveProblemMessages)
+}
+
+func buildProblem(t *testing.T) {
+	err := errors.E(
+		"cannot update user",
+		errors.WithMeta(MetaKeyType, "https://example.com/probs/invalid", "field", "email"),
+		errors.E("email is required", errors.Invalid, errors.Code("user.invalid"), errors.WithMeta("field", "inner")),
+	)
+
+	p := NewProblem(err)
+
+	if p.Status != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got: %d", http.StatusBadRequest, p.Status)
+	}
+
+	if p.Type != "https://example.com/probs/invalid" {
+		t.Fatalf("expected type from meta, got: %s", p.Type)
+	}
+
+	if p.Title != http.StatusText(http.StatusBadRequest) {
+		t.Fatalf("expected title to be the status text, got: %s", p.Title)
+	}
+
+	if p.Detail != "cannot update user" {
+		t.Fatalf("expected detail to be the outermost message, got: %s", p.Detail)
+	}
+
+	if p.Extensions["field"] != "email" {
+		t.Fatalf("expected outer meta to take precedence, got: %+v", p.Extensions["field"])
+	}
+
+	if p.Extensions[ExtensionKind] != "invalid" || p.Extensions[ExtensionCode] != "user.invalid" {
+		t.Fatalf("expected kind and code extensions, got: %+v", p.Extensions)
+	}
+}
+
+func hideProblemDetails5xx(t *testing.T) {
+	err := errors.E("connection refused to 10.0.0.1", errors.Unavailable, errors.WithMeta("host", "10.0.0.1"))
+
+	p := NewProblem(err)
+
+	if p.Status != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got: %d", http.StatusServiceUnavailable, p.Status)
+	}
+
+	if len(p.Detail) > 0 {
+		t.Fatalf("expected empty detail, got: %s", p.Detail)
+	}
+
+	if _, has := p.Extensions["host"]; has {
+		t.Fatalf("expected meta to be hidden, got: %+v", p.Extensions)
+	}
+
+	if _, has := p.Extensions[ExtensionErrors]; has {
+		t.Fatalf("expected the chain to be hidden, got: %+v", p.Extensions)
+	}
+}
+
+func encodeProblemExtensions(t *testing.T) {
+	p := Problem{
+		Type:       "about:blank",
+		Status:     http.StatusNotFound,
+		Extensions: map[string]any{"userId": 10, "status": "overwrite"},
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", err.Error())
+	}
+
+	var cmp map[string]any
+	_ = json.Unmarshal(b, &cmp)
+
+	if cmp["userId"] != float64(10) {
+		t.Fatalf("expected extension at the top level, got: %s", string(b))
+	}
+
+	if cmp["status"] != float64(http.StatusNotFound) {
+		t.Fatalf("expected extensions not to overwrite standard members, got: %s", string(b))
+	}
+
+	var dp Problem
+	if err = json.Unmarshal(b, &dp); err != nil {
+		t.Fatalf("expected json unmarshal nil error, got: %s", err.Error())
+	}
+
+	if dp.Status != http.StatusNotFound || dp.Extensions["userId"] != float64(10) {
+		t.Fatalf("expected decoded problem to match, got: %+v", dp)
+	}
+}
+
+func writeProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/10?x=1", nil)
+
+	WriteProblem(w, r, errors.E("user not found", errors.NotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got: %d", http.StatusNotFound, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("expected %s content type, got: %s", ProblemContentType, ct)
+	}
+
+	if !strings.Contains(w.Body.String(), `"instance":"/users/10?x=1"`) {
+		t.Fatalf("expected the request uri as instance, got: %s", w.Body.String())
+	}
+}
+
+func decodeProblemChain(t *testing.T) {
+	err := errors.E("cannot load user", errors.WithMeta("userId", "10"), errors.E("user not found", errors.NotFound))
+
+	w := httptest.NewRecorder()
+	WriteProblem(w, httptest.NewRequest(http.MethodGet, "/users/10", nil), err)
+
+	derr, perr := DecodeProblem(w.Body)
+	if perr != nil {
+		t.Fatalf("expected problem decode nil error, got: %s", perr.Error())
+	}
+
+	if derr.Error() != "cannot load user" {
+		t.Fatalf("expected outermost message, got: %s", derr.Error())
+	}
+
+	if !errors.HasMessage(derr, "user not found") {
+		t.Fatalf("expected the chain to be rebuilt, got: %s", errors.PrettyPrint(derr))
+	}
+
+	if !errors.Is(derr, errors.NotFound) {
+		t.Fatalf("expected the kind to be preserved")
+	}
+
+	if Status(derr) != http.StatusNotFound {
+		t.Fatalf("expected status %d, got: %d", http.StatusNotFound, Status(derr))
+	}
+
+	m, _ := errors.GetMeta(derr)
+	if m["userId"] != "10" {
+		t.Fatalf("expected meta to be preserved, got: %+v", m)
+	}
+
+	var e *errors.Error
+	errors.As(errors.Unwrap(derr), &e)
+
+	if e.Source.IsZero() {
+		t.Fatalf("expected the original source to be preserved")
+	}
+}
+
+func decodeForeignProblem(t *testing.T) {
+	body := `{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.",` +
+		`"status":403,"detail":"Your current balance is 30, but that costs 50.","balance":30}`
+
+	derr, perr := DecodeProblem(strings.NewReader(body))
+	if perr != nil {
+		t.Fatalf("expected problem decode nil error, got: %s", perr.Error())
+	}
+
+	if derr.Error() != "Your current balance is 30, but that costs 50." {
+		t.Fatalf("expected the detail as message, got: %s", derr.Error())
+	}
+
+	m, _ := errors.GetMeta(derr)
+	if m["balance"] != float64(30) {
+		t.Fatalf("expected extensions as meta, got: %+v", m)
+	}
+
+	if Status(derr) != http.StatusForbidden {
+		t.Fatalf("expected status %d, got: %d", http.StatusForbidden, Status(derr))
+	}
+}
+
+func redactProblemMeta(t *testing.T) {
+	err := errors.E("invalid email", errors.Invalid, errors.WithMeta("email", errors.Secret("john@example.com")))
+
+	b, jerr := json.Marshal(NewProblem(err))
+	if jerr != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", jerr.Error())
+	}
+
+	if strings.Contains(string(b), "john@example.com") || !strings.Contains(string(b), errors.RedactedValue) {
+		t.Fatalf("expected the secret value to be redacted, got: %s", string(b))
+	}
+}
+
+func publicProblemDetail(t *testing.T) {
+	err := errors.E("insert user: duplicate key", errors.Conflict, errors.Public("the email is already used"),
+		errors.WithMeta("sql", "insert into users", "email", "a@b.c"))
+
+	p := NewProblem(err)
+	if p.Detail != "the email is already used" {
+		t.Fatalf("expected the public message as detail, got: %s", p.Detail)
+	}
+
+	if len(p.Extensions) != 1 || p.Extensions[ExtensionKind] != "conflict" {
+		t.Fatalf("expected only the kind extension, got: %+v", p.Extensions)
+	}
+
+	err = errors.E("pq: connection reset", errors.Internal, errors.Public("please retry later"))
+
+	if p := NewProblem(err); p.Detail != "please retry later" {
+		t.Fatalf("expected the public message as detail for 5xx, got: %s", p.Detail)
+	}
+}
+
+func resolveProblemMessages(t *testing.T) {
+	err := errors.E("user {id} not found", errors.TemplateMsg, errors.NotFound, errors.WithMeta("id", 10))
+
+	w := httptest.NewRecorder()
+	WriteProblem(w, httptest.NewRequest(http.MethodGet, "/users/10", nil), err)
+
+	if strings.Contains(w.Body.String(), `"msg":"user {id} not found"`) ||
+		!strings.Contains(w.Body.String(), `"msg":"user 10 not found"`) {
+		t.Fatalf("expected the resolved message in the errors member, got: %s", w.Body.String())
+	}
+
+	derr, _ := DecodeProblem(w.Body)
+
+	var e *errors.Error
+	errors.As(derr, &e)
+
+	if e.Error() != "user 10 not found" || e.RawMessage() != "user {id} not found" {
+		t.Fatalf("expected the template to be decoded, got: %s", e.RawMessage())
+	}
+}
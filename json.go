@@ -1,26 +1,51 @@
 package errors
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
+// jsonError has the same fields as Error without its methods so it can be decoded without calling UnmarshalJSON
+// recursively.
+type jsonError Error
+
 // MarshalJSON implements json.Marshaler for Error. Need to use a workaround for encoding because it will create
 // an infinite loop if json.Marshal() is used in MarshalJSON().
+//
+// A single error is encoded as an object. An error tree is encoded as an array in the depth-first order of Flatten,
+// every element has a "depth" member with its depth in the tree, see Walk, so errors with multiple causes can be
+// rebuilt by UnmarshalJSON. The "msg" member is the message returned by Error, the message template is added as
+// "rawMsg" when it differs, see TemplateMsg.
 func (e *Error) MarshalJSON() ([]byte, error) {
-	errs := Flatten(e)
+	return marshalJSONTree(e)
+}
+
+// marshalJSONTree encodes a single error as an object and an error tree as an array with the depth of each element.
+func marshalJSONTree(err error) ([]byte, error) {
+	var (
+		errs   []Error
+		depths []int
+	)
+
+	Walk(err, func(wErr error, depth int) bool {
+		errs = append(errs, toError(wErr))
+		depths = append(depths, depth)
+
+		return true
+	})
 
 	var b []byte
 
 	if len(errs) == 1 {
-		b, err := marshalJSONErr(errs[0])
+		b, err := marshalJSONErr(errs[0], nil)
 		return b, err
 	}
 
 	// open json array
 	b = append(b, '[')
 
-	for _, err := range errs {
-		ib, ierr := marshalJSONErr(err)
+	for i, err := range errs {
+		ib, ierr := marshalJSONErr(err, &depths[i])
 		if ierr != nil {
 			return ib, ierr
 		}
@@ -38,8 +63,182 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 	return b, nil
 }
 
-func marshalJSONErr(err Error) ([]byte, error) {
-	b, merr := json.Marshal(err)
+// MarshalJSON implements json.Marshaler for Meta, sensitive values are redacted, see Meta.Redacted.
+func (p Meta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any(p.Redacted()))
+}
+
+// jsonErrorOutput shadows the Source field of the embedded error with a pointer so the zero Source can be left out,
+// omitempty has no effect on struct fields. The message template is kept in RawMsg when it differs from the resolved
+// message, see TemplateMsg.
+type jsonErrorOutput struct {
+	jsonError
+	Source *Source `json:"source,omitempty"`
+	RawMsg string  `json:"rawMsg,omitempty"`
+	Depth  *int    `json:"depth,omitempty"`
+}
+
+// jsonErrorInput is an error of the single object or the array form with the message template and the depth of the
+// error in the tree, see MarshalJSON.
+type jsonErrorInput struct {
+	jsonError
+	RawMsg *string `json:"rawMsg"`
+	Depth  *int    `json:"depth"`
+}
+
+// toError returns the decoded error, the message template is restored from rawMsg.
+func (in jsonErrorInput) toError() *Error {
+	e := Error(in.jsonError)
+	e.decoded = true
+
+	if in.RawMsg != nil {
+		e.Msg = *in.RawMsg
+		e.tmplMsg = true
+	}
+
+	return &e
+}
+
+func marshalJSONErr(err Error, depth *int) ([]byte, error) {
+	out := jsonErrorOutput{jsonError: jsonError(err), Depth: depth}
+	if !err.Source.IsZero() {
+		out.Source = &err.Source
+	}
+
+	// Encode the resolved message so clients don't receive the template.
+	if msg := err.message(); msg != err.Msg {
+		out.Msg = msg
+		out.RawMsg = err.Msg
+	}
+
+	b, merr := json.Marshal(out)
 
 	return b, merr
 }
+
+// UnmarshalJSON implements json.Unmarshaler for Error. It accepts both the single object and the array forms produced
+// by MarshalJSON. In the array form the first element is decoded into e and the rest of the elements are linked as the
+// wrapped errors using their depth, errors with multiple causes get all of them back, so Unwrap, Flatten and the
+// matchers work on the decoded value. Arrays without depths are linked as a linear chain.
+//
+// If the array has multiple errors at depth 0, ex. encoded from the stdlib errors.Join, e has no message and wraps all
+// of them. FromJSON returns them joined instead.
+func (e *Error) UnmarshalJSON(b []byte) error {
+	roots, err := decodeJSONTree(b)
+	if err != nil {
+		return err
+	}
+
+	switch len(roots) {
+	case 0:
+		*e = Error{}
+	case 1:
+		*e = *roots[0]
+	default:
+		*e = Error{err: joinRoots(roots)}
+	}
+
+	return nil
+}
+
+// decodeJSONTree decodes the payload produced by MarshalJSON and returns the errors at depth 0.
+func decodeJSONTree(b []byte) ([]*Error, error) {
+	b = bytes.TrimSpace(b)
+
+	if bytes.Equal(b, []byte("null")) {
+		return nil, nil
+	}
+
+	if len(b) == 0 || b[0] != '[' {
+		var in jsonErrorInput
+
+		if err := json.Unmarshal(b, &in); err != nil {
+			return nil, err
+		}
+
+		return []*Error{in.toError()}, nil
+	}
+
+	var elems []jsonErrorInput
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return nil, err
+	}
+
+	// Payloads without depths were encoded as a linear chain.
+	linear := true
+	for _, elem := range elems {
+		if elem.Depth != nil {
+			linear = false
+			break
+		}
+	}
+
+	var (
+		roots    []*Error
+		nodes    = make([]*Error, len(elems))
+		children = make([][]error, len(elems))
+		parents  []int // index of the last error at each depth
+	)
+
+	for i, elem := range elems {
+		depth := i
+		if !linear {
+			if elem.Depth == nil || *elem.Depth < 0 || *elem.Depth > len(parents) {
+				return nil, E("invalid error depth in JSON payload", WithMeta("index", i))
+			}
+
+			depth = *elem.Depth
+		}
+
+		nodes[i] = elem.toError()
+		parents = append(parents[:depth], i)
+
+		if depth == 0 {
+			roots = append(roots, nodes[i])
+			continue
+		}
+
+		p := parents[depth-1]
+		children[p] = append(children[p], nodes[i])
+	}
+
+	for i, n := range nodes {
+		switch len(children[i]) {
+		case 0:
+		case 1:
+			n.err = children[i][0]
+		default:
+			n.err = &joinError{errs: children[i]}
+		}
+	}
+
+	return roots, nil
+}
+
+// joinRoots groups the errors at depth 0 of a decoded payload.
+func joinRoots(roots []*Error) error {
+	errs := make([]error, len(roots))
+	for i, r := range roots {
+		errs[i] = r
+	}
+
+	return &joinError{errs: errs}
+}
+
+// FromJSON decodes a JSON payload produced by MarshalJSON back to an error tree, see UnmarshalJSON. It returns a nil
+// error if b is null or an empty array. The second returned argument is not nil if b can't be decoded.
+func FromJSON(b []byte) (error, error) {
+	roots, err := decodeJSONTree(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(roots) {
+	case 0:
+		return nil, nil
+	case 1:
+		return roots[0], nil
+	default:
+		return joinRoots(roots), nil
+	}
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -23,6 +24,17 @@ func TestJSONMarshaling(t *testing.T) {
 	t.Run("it should work with single error", singleErrorJSONPayload)
 }
 
+func TestJSONUnmarshaling(t *testing.T) {
+	t.Run("it should rebuild the error chain", rebuildErrorChain)
+	t.Run("it should decode a single error", decodeSingleError)
+	t.Run("it should decode errors embedded in a payload", decodeEmbeddedErrors)
+	t.Run("it should return nil for null and empty payloads", decodeNullPayload)
+	t.Run("it should fail on invalid payload", decodeInvalidPayload)
+	t.Run("it should keep the branches of the error tree", keepJSONBranches)
+	t.Run("it should decode joined root errors", decodeJoinedRoots)
+	t.Run("it should decode arrays without depths as a chain", decodeLinearPayload)
+}
+
 func correctJSONPayload(t *testing.T) {
 	regErr1 := fmt.Errorf("reg error 1")
 	regErr2 := fmt.Errorf("reg error 2: %w", regErr1)
@@ -102,3 +114,169 @@ func singleErrorJSONPayload(t *testing.T) {
 		t.Fatalf("expected cmp.Err.Meta length 0, got: %d", len(cmp.Err.Meta))
 	}
 }
+
+func rebuildErrorChain(t *testing.T) {
+	regErr := fmt.Errorf("reg error")
+	err1 := E("err1 error", WithMeta("test", "val"), NotFound, regErr)
+	err2 := E("err2", err1, WithMeta("outer", true))
+
+	b, err := json.Marshal(err2)
+	if err != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", err.Error())
+	}
+
+	derr, err := FromJSON(b)
+	if err != nil {
+		t.Fatalf("expected FromJSON nil error, got: %s", err.Error())
+	}
+
+	errs := Flatten(derr)
+	if len(errs) != 3 {
+		t.Fatalf("expected decoded chain length 3, got: %d", len(errs))
+	}
+
+	if derr.Error() != "err2" {
+		t.Fatalf("expected outermost message err2, got: %s", derr.Error())
+	}
+
+	uErr := Unwrap(derr)
+	if uErr == nil || uErr.Error() != "err1 error" {
+		t.Fatalf("expected Unwrap() to return err1 error, got: %+v", uErr)
+	}
+
+	if !HasMessage(derr, "reg error") {
+		t.Fatalf("expected HasMessage() to match the innermost error")
+	}
+
+	if KindOf(derr) != NotFound {
+		t.Fatalf("expected kind %s, got: %s", NotFound, KindOf(derr))
+	}
+
+	m, _ := GetMeta(derr)
+	if m["outer"] != true {
+		t.Fatalf("expected outer meta, got: %+v", m)
+	}
+
+	m, _ = GetMeta(uErr)
+	if m["test"] != "val" {
+		t.Fatalf("expected inner meta, got: %+v", m)
+	}
+
+	if errs[1].Source.String() != err1.(*Error).Source.String() {
+		t.Fatalf("expected source %s, got: %s", err1.(*Error).Source, errs[1].Source)
+	}
+}
+
+func decodeSingleError(t *testing.T) {
+	b, _ := json.Marshal(E("single", WithMeta("key", "val")))
+
+	derr, err := FromJSON(b)
+	if err != nil {
+		t.Fatalf("expected FromJSON nil error, got: %s", err.Error())
+	}
+
+	if derr.Error() != "single" {
+		t.Fatalf("expected message single, got: %s", derr.Error())
+	}
+
+	if Unwrap(derr) != nil {
+		t.Fatalf("expected no wrapped error, got: %+v", Unwrap(derr))
+	}
+}
+
+func decodeEmbeddedErrors(t *testing.T) {
+	var payload dummyType
+	payload.Err = E("err2", E("err1"))
+
+	b, _ := json.Marshal(payload)
+
+	var cmp struct {
+		Err *Error `json:"err"`
+	}
+
+	if err := json.Unmarshal(b, &cmp); err != nil {
+		t.Fatalf("expected json unmarshal nil error, got: %s", err.Error())
+	}
+
+	if !HasMessage(cmp.Err, "err1") {
+		t.Fatalf("expected the embedded chain to be rebuilt, got: %+v", Flatten(cmp.Err))
+	}
+}
+
+func decodeNullPayload(t *testing.T) {
+	for _, p := range []string{"null", "[]", " null "} {
+		derr, err := FromJSON([]byte(p))
+		if err != nil || derr != nil {
+			t.Fatalf("expected nil, nil for %q, got: %+v, %+v", p, derr, err)
+		}
+	}
+}
+
+func decodeInvalidPayload(t *testing.T) {
+	_, err := FromJSON([]byte(`{"msg":`))
+	if err == nil {
+		t.Fatalf("expected FromJSON to fail on invalid payload")
+	}
+}
+
+func keepJSONBranches(t *testing.T) {
+	err := E("outer", E("a", E("a1")), E("b"))
+
+	b, _ := json.Marshal(err)
+
+	derr, jerr := FromJSON(b)
+	if jerr != nil {
+		t.Fatalf("expected json decode nil error, got: %s", jerr.Error())
+	}
+
+	want := PrettyTree(err, &TreeOptions{HideMeta: true, Color: ColorNever})
+	got := PrettyTree(derr, &TreeOptions{HideMeta: true, Color: ColorNever})
+
+	if got != want {
+		t.Fatalf("expected the same tree:\n%s\ngot:\n%s", want, got)
+	}
+
+	causes, ok := Unwrap(derr).(interface{ Unwrap() []error })
+	if !ok || len(causes.Unwrap()) != 2 {
+		t.Fatalf("expected a and b to be sibling causes, got: %s", PrettyTree(derr, nil))
+	}
+}
+
+func decodeJoinedRoots(t *testing.T) {
+	b, _ := json.Marshal(&Error{Msg: "wrapper", err: errors.Join(E("a"), E("b", E("b1")))})
+
+	derr, _ := FromJSON(b)
+
+	var msgs []string
+	Walk(derr, func(wErr error, depth int) bool {
+		msgs = append(msgs, fmt.Sprintf("%d:%s", depth, wErr.(*Error).Msg))
+		return true
+	})
+
+	if fmt.Sprint(msgs) != "[0:wrapper 1:a 1:b 2:b1]" {
+		t.Fatalf("expected the joined causes, got: %v", msgs)
+	}
+
+	derr, _ = FromJSON([]byte(`[{"msg":"a","depth":0},{"msg":"b","depth":0}]`))
+	if derr.Error() != "a\nb" || len(Flatten(derr)) != 2 {
+		t.Fatalf("expected the roots to be joined, got: %s", derr.Error())
+	}
+
+	if _, jerr := FromJSON([]byte(`[{"msg":"a","depth":0},{"msg":"b","depth":2}]`)); jerr == nil {
+		t.Fatalf("expected an error for an invalid depth")
+	}
+}
+
+func decodeLinearPayload(t *testing.T) {
+	derr, _ := FromJSON([]byte(`[{"msg":"a"},{"msg":"b"},{"msg":"c"}]`))
+
+	var depths []int
+	Walk(derr, func(_ error, depth int) bool {
+		depths = append(depths, depth)
+		return true
+	})
+
+	if fmt.Sprint(depths) != "[0 1 2]" {
+		t.Fatalf("expected a linear chain, got: %v", depths)
+	}
+}
@@ -0,0 +1,44 @@
+package errors
+
+// Key is a typed Meta key. Libraries can declare their keys once and read or set the values without type assertions:
+//
+//	var UserID = errors.NewKey[int64]("user_id")
+//
+//	err := errors.E("user not found", UserID.With(10))
+//	id, ok := UserID.Get(err)
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new Key with name as the Meta key.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the Meta key.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// With returns a new Meta with the key set to v, pass it to E or M to set it on the error.
+func (k Key[T]) With(v T) Meta {
+	return WithMeta(k.name, v)
+}
+
+// From returns the value of the key in m converted to T, see Meta.GetInt for the numeric conversions. The second
+// returned argument is FALSE if the key is not set or its value can't be converted to T.
+func (k Key[T]) From(m Meta) (T, bool) {
+	return metaValue[T](m, k.name)
+}
+
+// Get returns the value of the key from the outermost error in err's chain that has it set. The second returned
+// argument is FALSE if none of the errors have the key set or its value can't be converted to T.
+func (k Key[T]) Get(err error) (T, bool) {
+	v, has := LookupMeta(err, k.name)
+	if !has {
+		var zero T
+		return zero, false
+	}
+
+	return convertMetaValue[T](v)
+}
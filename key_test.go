@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var (
+	testUserID = NewKey[int64]("user_id")
+	testTenant = NewKey[string]("tenant")
+)
+
+func TestKey(t *testing.T) {
+	t.Run("it should set and get typed values", setGetTypedKey)
+	t.Run("it should search the whole chain", keySearchesChain)
+	t.Run("it should convert values decoded from json", keyConvertsJSON)
+	t.Run("it should fail on wrong types", keyWrongType)
+}
+
+func setGetTypedKey(t *testing.T) {
+	err := E("test", testUserID.With(10), testTenant.With("acme"))
+
+	if id, ok := testUserID.Get(err); !ok || id != 10 {
+		t.Fatalf("Get() expected 10, got: %v, %v", id, ok)
+	}
+
+	if tenant, ok := testTenant.Get(err); !ok || tenant != "acme" {
+		t.Fatalf("Get() expected acme, got: %v, %v", tenant, ok)
+	}
+
+	if testUserID.Name() != "user_id" {
+		t.Fatalf("Name() expected user_id, got: %s", testUserID.Name())
+	}
+}
+
+func keySearchesChain(t *testing.T) {
+	err := E("outer", testTenant.With("outer"), E("middle", E("inner", testUserID.With(5), testTenant.With("inner"))))
+
+	if id, ok := testUserID.Get(err); !ok || id != 5 {
+		t.Fatalf("Get() expected 5 from the inner error, got: %v, %v", id, ok)
+	}
+
+	if tenant, _ := testTenant.Get(err); tenant != "outer" {
+		t.Fatalf("Get() expected the outermost value, got: %s", tenant)
+	}
+
+	if _, ok := testUserID.Get(E("no meta")); ok {
+		t.Fatalf("Get() expected false when the key is not set")
+	}
+}
+
+func keyConvertsJSON(t *testing.T) {
+	b, _ := json.Marshal(E("test", testUserID.With(42)))
+
+	derr, _ := FromJSON(b)
+
+	if id, ok := testUserID.Get(derr); !ok || id != 42 {
+		t.Fatalf("Get() expected 42 from the decoded error, got: %v, %v", id, ok)
+	}
+}
+
+func keyWrongType(t *testing.T) {
+	err := E("test", WithMeta("user_id", "not a number"))
+
+	if _, ok := testUserID.Get(err); ok {
+		t.Fatalf("Get() expected false for a value of a different type")
+	}
+}
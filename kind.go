@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+)
+
+// Kind classifies an error so callers can act on the class of the error instead of matching on its message. The zero
+// value Other is the default and means the error is not classified.
+type Kind uint8 //nolint:errname // used as an errors.Is target
+
+// Kinds of errors. Add new kinds at the end to keep the numeric values stable.
+const (
+	Other           Kind = iota // Unclassified error.
+	Invalid                     // Invalid input or operation.
+	Unauthenticated             // Missing or invalid credentials.
+	Permission                  // Permission denied.
+	NotFound                    // Item does not exist.
+	Conflict                    // Item already exists or is in a conflicting state.
+	Internal                    // Internal error or inconsistency.
+	Unavailable                 // Service or resource is temporarily unavailable.
+	Timeout                     // Operation timed out.
+)
+
+var kindNames = [...]string{
+	Other:           "other",
+	Invalid:         "invalid",
+	Unauthenticated: "unauthenticated",
+	Permission:      "permission",
+	NotFound:        "not_found",
+	Conflict:        "conflict",
+	Internal:        "internal",
+	Unavailable:     "unavailable",
+	Timeout:         "timeout",
+}
+
+// String returns the name of the kind and satisfies the fmt.Stringer interface.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+
+	return fmt.Sprintf("kind(%d)", k)
+}
+
+// Error returns the name of the kind. Kind satisfies the stdlib error interface so it can be used as a target in Is,
+// ex. errors.Is(err, errors.NotFound).
+func (k Kind) Error() string {
+	return k.String()
+}
+
+// MarshalText implements encoding.TextMarshaler, a Kind is encoded by its name.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Unknown kind names are decoded as Other.
+func (k *Kind) UnmarshalText(b []byte) error {
+	*k = Other
+
+	for i, name := range kindNames {
+		if name == string(b) {
+			*k = Kind(i)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Code is an application defined error code, ex. "user.not_found". Pass it to E or M to set the code on the error.
+type Code string
+
+// KindOf returns the outermost non-default Kind in err's chain, or Other if none of the errors in the chain have a
+// kind set.
+func KindOf(err error) Kind {
+	for _, e := range Flatten(err) {
+		if e.Kind != Other {
+			return e.Kind
+		}
+	}
+
+	return Other
+}
+
+// CodeOf returns the outermost non-empty Code in err's chain, or an empty Code if none of the errors in the chain have
+// a code set.
+func CodeOf(err error) Code {
+	for _, e := range Flatten(err) {
+		if len(e.Code) > 0 {
+			return e.Code
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	t.Run("it should store kind and code", storeKindAndCode)
+	t.Run("it should return the outermost kind", outermostKind)
+	t.Run("it should return the outermost code", outermostCode)
+	t.Run("it should match kind with Is", matchKindWithIs)
+	t.Run("M() should preserve kind and code", mirrorKindAndCode)
+	t.Run("it should encode kind in json", encodeKindJSON)
+	t.Run("it should print kind in PrettyPrint", prettyPrintKind)
+}
+
+func storeKindAndCode(t *testing.T) {
+	e := E("test error", NotFound, Code("user.not_found"))
+	ee := e.(*Error)
+
+	if ee.Kind != NotFound {
+		t.Fatalf("E() should store Kind, expected: %s, got: %s", NotFound, ee.Kind)
+	}
+
+	if ee.Code != "user.not_found" {
+		t.Fatalf("E() should store Code, expected: user.not_found, got: %s", ee.Code)
+	}
+}
+
+func outermostKind(t *testing.T) {
+	e0 := errors.New("regular error")
+	e1 := E("e1", Internal, e0)
+	e2 := E("e2", e1)
+	e3 := E("e3", Invalid, e2)
+
+	if k := KindOf(e2); k != Internal {
+		t.Fatalf("KindOf() expected: %s, got: %s", Internal, k)
+	}
+
+	if k := KindOf(e3); k != Invalid {
+		t.Fatalf("KindOf() expected: %s, got: %s", Invalid, k)
+	}
+
+	if k := KindOf(e0); k != Other {
+		t.Fatalf("KindOf() on a regular error expected: %s, got: %s", Other, k)
+	}
+
+	if k := KindOf(nil); k != Other {
+		t.Fatalf("KindOf() on nil expected: %s, got: %s", Other, k)
+	}
+}
+
+func outermostCode(t *testing.T) {
+	e1 := E("e1", Code("inner"))
+	e2 := E("e2", e1)
+	e3 := E("e3", Code("outer"), e2)
+
+	if c := CodeOf(e2); c != "inner" {
+		t.Fatalf("CodeOf() expected: inner, got: %s", c)
+	}
+
+	if c := CodeOf(e3); c != "outer" {
+		t.Fatalf("CodeOf() expected: outer, got: %s", c)
+	}
+}
+
+func matchKindWithIs(t *testing.T) {
+	e1 := E("e1", NotFound)
+	e2 := fmt.Errorf("wrapped: %w", E("e2", e1))
+
+	if Is(e2, NotFound) == false {
+		t.Fatalf("Is() should match the kind in the chain")
+	}
+
+	if Is(e2, Permission) == true {
+		t.Fatalf("Is() shouldn't match a kind that's not in the chain")
+	}
+
+	if Is(E("no kind"), Other) == true {
+		t.Fatalf("Is() should never match Other")
+	}
+}
+
+func mirrorKindAndCode(t *testing.T) {
+	e1 := E("e1", Conflict, Code("dup"))
+	e2 := M(e1, WithMeta("key", "val"))
+
+	var ee2 *Error
+	As(e2, &ee2)
+
+	if ee2.Kind != Conflict || ee2.Code != "dup" {
+		t.Fatalf("M() should copy kind and code, got: %s, %s", ee2.Kind, ee2.Code)
+	}
+
+	e3 := M(e1, Invalid)
+	if k := KindOf(e3); k != Invalid {
+		t.Fatalf("M() should overwrite kind, expected: %s, got: %s", Invalid, k)
+	}
+}
+
+func encodeKindJSON(t *testing.T) {
+	b, err := json.Marshal(E("test", Unavailable, Code("svc.down")))
+	if err != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", err.Error())
+	}
+
+	if !strings.Contains(string(b), `"kind":"unavailable"`) || !strings.Contains(string(b), `"code":"svc.down"`) {
+		t.Fatalf("expected kind and code in json payload, got: %s", string(b))
+	}
+
+	var cmp Error
+	err = json.Unmarshal(b, &cmp)
+	if err != nil {
+		t.Fatalf("expected json unmarshal nil error, got: %s", err.Error())
+	}
+
+	if cmp.Kind != Unavailable {
+		t.Fatalf("expected decoded kind %s, got: %s", Unavailable, cmp.Kind)
+	}
+
+	b, _ = json.Marshal(E("test"))
+	if strings.Contains(string(b), `"kind"`) {
+		t.Fatalf("expected Other kind to be omitted, got: %s", string(b))
+	}
+}
+
+func prettyPrintKind(t *testing.T) {
+	out := PrettyPrint(E("test", Permission))
+
+	if !strings.Contains(out, "|- Kind : permission") {
+		t.Fatalf("expected kind in PrettyPrint output, got: %s", out)
+	}
+}
@@ -2,14 +2,16 @@ package errors
 
 import "strings"
 
-// HasMessage reports whether any error in err's chain matches msg. It performs a case-insensitive matching.
+// HasMessage reports whether any error in err's chain, including every branch of errors with multiple causes, matches
+// msg. It performs a case-insensitive matching against both the raw message and the message with the Meta placeholders
+// resolved, see TemplateMsg.
 //
 // This is helpful when the error type's in err's chain is unknown and a string matching is preferred.
 func HasMessage(err error, msg string) bool {
 	errs := Flatten(err)
 
 	for _, e := range errs {
-		if strings.EqualFold(e.Msg, msg) {
+		if strings.EqualFold(e.Msg, msg) || strings.EqualFold(e.message(), msg) {
 			return true
 		}
 	}
@@ -17,7 +19,9 @@ func HasMessage(err error, msg string) bool {
 	return false
 }
 
-// ContainsMessage reports whether any error in err's chain contains msg. It performs a case-insensitive matching.
+// ContainsMessage reports whether any error in err's chain, including every branch of errors with multiple causes,
+// contains msg. It performs a case-insensitive matching against both the raw message and the message with the Meta
+// placeholders resolved, see TemplateMsg.
 //
 // This is helpful when the error type's in err's chain is unknown and a string matching is preferred.
 func ContainsMessage(err error, msg string) bool {
@@ -25,7 +29,8 @@ func ContainsMessage(err error, msg string) bool {
 	lowerMsg := strings.ToLower(msg)
 
 	for _, e := range errs {
-		if strings.Contains(strings.ToLower(e.Msg), lowerMsg) {
+		if strings.Contains(strings.ToLower(e.Msg), lowerMsg) ||
+			strings.Contains(strings.ToLower(e.message()), lowerMsg) {
 			return true
 		}
 	}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageMode selects whether the message of an error is a template, pass it to E, M or Define. Messages are literal
+// by default so messages built from other errors or from user input are never changed.
+type MessageMode uint8
+
+// Message modes.
+const (
+	LiteralMsg  MessageMode = iota + 1 // The message is rendered as is.
+	TemplateMsg                        // The {key} placeholders of the message are resolved from the Meta, see Error.
+)
+
+// RawMessage returns the message of e without resolving the {key} placeholders, the same for every error created from
+// the same message template so it can be used to group errors. See Error.
+func (e *Error) RawMessage() string {
+	return e.Msg
+}
+
+// message returns the message of e with the placeholders resolved if the message is a template, see TemplateMsg.
+func (e *Error) message() string {
+	if !e.tmplMsg {
+		return e.Msg
+	}
+
+	return interpolate(e.Msg, e.Meta)
+}
+
+// interpolate replaces the {key} placeholders of the template msg with the values of m, sensitive values are redacted.
+// A key must start with a letter or an underscore and contain only letters, digits, underscores, dots and dashes,
+// other braces are kept as is. Placeholders of missing keys are rendered as {!MISSING:key}. Use {{ and }} for literal
+// braces.
+func interpolate(msg string, m Meta) string {
+	if strings.IndexByte(msg, '{') < 0 && !strings.Contains(msg, "}}") {
+		return msg
+	}
+
+	m = m.Redacted()
+
+	var b strings.Builder
+	b.Grow(len(msg))
+
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+
+		if (c == '{' || c == '}') && i+1 < len(msg) && msg[i+1] == c {
+			b.WriteByte(c)
+			i++
+
+			continue
+		}
+
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(msg[i+1:], '}')
+		if end < 0 || !isPlaceholderKey(msg[i+1:i+1+end]) {
+			b.WriteByte(c)
+			continue
+		}
+
+		key := msg[i+1 : i+1+end]
+
+		if v, has := m[key]; has {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString("{!MISSING:" + key + "}")
+		}
+
+		i += end + 1
+	}
+
+	return b.String()
+}
+
+// isPlaceholderKey reports whether key is a valid placeholder key, see interpolate.
+func isPlaceholderKey(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
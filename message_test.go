@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	t.Run("it should resolve placeholders from meta", resolvePlaceholders)
+	t.Run("it should render missing keys", renderMissingKeys)
+	t.Run("it should redact sensitive values", redactPlaceholders)
+	t.Run("it should keep braces which are not placeholders", keepLiteralBraces)
+	t.Run("it should keep the raw template", keepRawTemplate)
+	t.Run("it should match both messages", matchTemplateMessages)
+	t.Run("it should keep literal messages as is", keepLiteralMessages)
+	t.Run("it should encode the resolved message in JSON", encodeResolvedMessage)
+}
+
+func resolvePlaceholders(t *testing.T) {
+	err := E("user {user_id} not found in {org.name}", TemplateMsg, WithMeta("user_id", 10, "org.name", "acme"))
+
+	if err.Error() != "user 10 not found in acme" {
+		t.Fatalf("expected the placeholders to be resolved, got: %s", err.Error())
+	}
+
+	err = M(err, WithMeta("user_id", 11))
+	if err.Error() != "user 11 not found in acme" {
+		t.Fatalf("expected the placeholders to use the mirrored meta, got: %s", err.Error())
+	}
+}
+
+func renderMissingKeys(t *testing.T) {
+	err := E("user {user_id} not found", TemplateMsg)
+
+	if err.Error() != "user {!MISSING:user_id} not found" {
+		t.Fatalf("expected the missing key to be rendered, got: %s", err.Error())
+	}
+}
+
+func redactPlaceholders(t *testing.T) {
+	err := E("invalid token {token}", TemplateMsg, WithMeta("token", Secret("abc123")))
+
+	if err.Error() != "invalid token "+RedactedValue {
+		t.Fatalf("expected the value to be redacted, got: %s", err.Error())
+	}
+}
+
+func keepLiteralBraces(t *testing.T) {
+	tests := map[string]string{
+		`invalid json {"a":1}`:    `invalid json {"a":1}`,
+		"escaped {{user_id}}":     "escaped {user_id}",
+		"unclosed {user_id":       "unclosed {user_id",
+		"empty {} and { spaced }": "empty {} and { spaced }",
+		"closing }} only":         "closing } only",
+	}
+
+	for msg, want := range tests {
+		if got := E(msg, TemplateMsg, WithMeta("user_id", 10)).Error(); got != want {
+			t.Fatalf("expected %s, got: %s", want, got)
+		}
+	}
+}
+
+func keepRawTemplate(t *testing.T) {
+	err := E("user {user_id} not found", TemplateMsg, WithMeta("user_id", 10))
+
+	var e *Error
+	As(err, &e)
+
+	if e.RawMessage() != "user {user_id} not found" || e.Msg != e.RawMessage() {
+		t.Fatalf("expected the raw template, got: %s", e.RawMessage())
+	}
+
+	if !strings.Contains(PrettyPrint(err), "user 10 not found") {
+		t.Fatalf("expected the resolved message in PrettyPrint, got: %s", PrettyPrint(err))
+	}
+}
+
+func matchTemplateMessages(t *testing.T) {
+	err := E("cannot load profile", E("user {user_id} not found", TemplateMsg, WithMeta("user_id", 10)))
+
+	if !HasMessage(err, "user {user_id} not found") || !HasMessage(err, "user 10 not found") {
+		t.Fatalf("expected both the template and the resolved message to match")
+	}
+
+	if !ContainsMessage(err, "user 10") {
+		t.Fatalf("expected the resolved message to be matched")
+	}
+}
+
+func keepLiteralMessages(t *testing.T) {
+	payload := `bad payload {"a":{"b":1}}`
+	if err := E(payload, WithMeta("a", 1)); err.Error() != payload {
+		t.Fatalf("expected the literal message, got: %s", err.Error())
+	}
+
+	lookup := fmt.Errorf("lookup {host}: no such host")
+	if err := M(lookup, WithMeta("dsn", "postgres://secret")); err.Error() != lookup.Error() {
+		t.Fatalf("expected the message of the regular error, got: %s", err.Error())
+	}
+
+	err := E("user {dsn} not found", TemplateMsg, WithMeta("dsn", "postgres://secret"), E("{dsn}"))
+	if errs := Flatten(err); errs[1].Error() != "{dsn}" {
+		t.Fatalf("expected the wrapped message to stay literal, got: %s", errs[1].Error())
+	}
+
+	if err = M(fmt.Errorf("wrapped: %w", err)); err.Error() != "wrapped: user postgres://secret not found" {
+		t.Fatalf("expected the message of the regular error to be kept, got: %s", err.Error())
+	}
+
+	err = func() (err error) {
+		defer Recover(&err)
+		panic("bad input {x}}")
+	}()
+
+	if err.Error() != "panic: bad input {x}}" {
+		t.Fatalf("expected the panic message as is, got: %s", err.Error())
+	}
+
+	err = M(E("user {id}", TemplateMsg, WithMeta("id", 10)), LiteralMsg)
+	if err.Error() != "user {id}" {
+		t.Fatalf("expected LiteralMsg to turn off the template, got: %s", err.Error())
+	}
+}
+
+func encodeResolvedMessage(t *testing.T) {
+	err := E("cannot load profile", E("user {user_id} not found", TemplateMsg, WithMeta("user_id", 10)))
+
+	b, _ := json.Marshal(err)
+	if !strings.Contains(string(b), `"msg":"user 10 not found"`) ||
+		!strings.Contains(string(b), `"rawMsg":"user {user_id} not found"`) {
+		t.Fatalf("expected the resolved message and the template in JSON, got: %s", string(b))
+	}
+
+	if strings.Count(string(b), "rawMsg") != 1 {
+		t.Fatalf("expected rawMsg only for the template, got: %s", string(b))
+	}
+
+	derr, _ := FromJSON(b)
+	if !HasMessage(derr, "user 10 not found") || !HasMessage(derr, "user {user_id} not found") {
+		t.Fatalf("expected the template to be decoded, got: %s", PrettyPrint(derr))
+	}
+
+	var e *Error
+	As(Unwrap(derr), &e)
+
+	if e.RawMessage() != "user {user_id} not found" || e.Error() != "user 10 not found" {
+		t.Fatalf("expected the template to be restored, got: %s", e.RawMessage())
+	}
+}
@@ -1,10 +1,15 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"time"
 )
 
-// Meta holds extra meta data around an error. Try adding simple values to the Meta map. Key order is not guaranteed.
+// Meta holds extra meta data around an error. Try adding simple values to the Meta map. Every output of Meta lists the
+// keys in sorted order, so the output is deterministic.
 type Meta map[string]any
 
 // WithMeta accepts an even number of arguments representing key/value pairs. The first argument "firstKey" forces
@@ -67,3 +72,184 @@ func (p Meta) Merge(firstKey string, args ...any) (m Meta) {
 
 	return p
 }
+
+// Keys returns the keys of p in sorted order.
+func (p Meta) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// GetString returns the string value of key. The second returned argument is FALSE if the key is not set or its value
+// is not a string.
+func (p Meta) GetString(key string) (string, bool) {
+	return metaValue[string](p, key)
+}
+
+// GetInt returns the integer value of key. Any integer or integral float value is converted, so numbers decoded from
+// JSON as float64 are accepted. The second returned argument is FALSE if the key is not set or its value can't be
+// converted.
+func (p Meta) GetInt(key string) (int, bool) {
+	return metaValue[int](p, key)
+}
+
+// GetBool returns the bool value of key. The second returned argument is FALSE if the key is not set or its value is
+// not a bool.
+func (p Meta) GetBool(key string) (bool, bool) {
+	return metaValue[bool](p, key)
+}
+
+// GetDuration returns the time.Duration value of key. Numbers are converted as nanoseconds, the same as the JSON
+// encoding of time.Duration, and strings are parsed with time.ParseDuration. The second returned argument is FALSE if
+// the key is not set or its value can't be converted.
+func (p Meta) GetDuration(key string) (time.Duration, bool) {
+	return metaValue[time.Duration](p, key)
+}
+
+// GetTime returns the time.Time value of key. Strings are parsed as RFC 3339 times, the same as the JSON encoding of
+// time.Time. The second returned argument is FALSE if the key is not set or its value can't be converted.
+func (p Meta) GetTime(key string) (time.Time, bool) {
+	return metaValue[time.Time](p, key)
+}
+
+// metaValue returns the value of key in p converted to T, Secret values are unwrapped.
+func metaValue[T any](p Meta, key string) (T, bool) {
+	v, has := p[key]
+	if !has {
+		var zero T
+		return zero, false
+	}
+
+	return convertMetaValue[T](v)
+}
+
+// convertMetaValue converts a Meta value to T, Secret values are unwrapped.
+func convertMetaValue[T any](v any) (T, bool) {
+	if s, ok := v.(SecretValue); ok {
+		v = s.Value()
+	}
+
+	return convertValue[T](v)
+}
+
+// convertValue converts v to T. Besides a direct type assertion, numbers are converted between the integer and float
+// types, and time.Duration and time.Time are converted from their JSON representations.
+func convertValue[T any](v any) (T, bool) {
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+
+	var zero T
+	var ret any
+	var ok bool
+
+	switch any(zero).(type) {
+	case int:
+		var n int64
+		n, ok = toInt64(v)
+		ret = int(n)
+	case int64:
+		ret, ok = toInt64(v)
+	case int32:
+		var n int64
+		n, ok = toInt64(v)
+		ok = ok && n >= math.MinInt32 && n <= math.MaxInt32
+		ret = int32(n)
+	case uint:
+		var n int64
+		n, ok = toInt64(v)
+		ok = ok && n >= 0
+		ret = uint(n)
+	case uint64:
+		var n int64
+		n, ok = toInt64(v)
+		ok = ok && n >= 0
+		ret = uint64(n)
+	case float64:
+		ret, ok = toFloat64(v)
+	case time.Duration:
+		if s, isStr := v.(string); isStr {
+			d, err := time.ParseDuration(s)
+			ret, ok = d, err == nil
+			break
+		}
+
+		var n int64
+		n, ok = toInt64(v)
+		ret = time.Duration(n)
+	case time.Time:
+		if s, isStr := v.(string); isStr {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			ret, ok = t, err == nil
+		}
+	}
+
+	if !ok {
+		return zero, false
+	}
+
+	t, ok := ret.(T)
+
+	return t, ok
+}
+
+// toInt64 converts any integer or integral float value to int64.
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), n <= math.MaxInt64
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case float32:
+		return toInt64(float64(n))
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+
+	return 0, false
+}
+
+// toFloat64 converts any integer or float value to float64.
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	i, ok := toInt64(v)
+
+	return float64(i), ok
+}
@@ -1,7 +1,11 @@
 package errors
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMeta(t *testing.T) {
@@ -12,6 +16,10 @@ func TestMeta(t *testing.T) {
 	t.Run("it should set !BADKEY string for non-string key in args", setBadKeyNonStringKey)
 	t.Run("it should set a key/value pair in the map", setKeyValuePair)
 	t.Run("it should merge map to existing map", mergeMaps)
+	t.Run("it should output keys in sorted order", sortedKeysOutput)
+	t.Run("it should return typed values", typedGetters)
+	t.Run("it should coerce values decoded from json", typedGettersFromJSON)
+	t.Run("it should fail on wrong types", typedGettersWrongType)
 }
 
 func storeMetaMap(t *testing.T) {
@@ -131,3 +139,101 @@ func mergeMaps(t *testing.T) {
 		t.Fatalf("Merge(), wrong value for key2, expected: 'val2', got: %+v", v)
 	}
 }
+
+func sortedKeysOutput(t *testing.T) {
+	m := WithMeta("zeta", 1, "alpha", 2, "mid", 3, "beta", 4)
+
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"alpha", "beta", "mid", "zeta"}) {
+		t.Fatalf("Keys() expected sorted keys, got: %+v", keys)
+	}
+
+	for i := 0; i < 20; i++ {
+		if s := m.String(); s != "[alpha:2 beta:4 mid:3 zeta:1]" {
+			t.Fatalf("String() expected sorted output, got: %s", s)
+		}
+	}
+
+	pretty := m.metaPrettyString()
+	if strings.Index(pretty, "alpha") > strings.Index(pretty, "beta") || strings.Index(pretty, "mid") > strings.Index(pretty, "zeta") {
+		t.Fatalf("metaPrettyString() expected sorted output, got: %s", pretty)
+	}
+}
+
+func typedGetters(t *testing.T) {
+	now := time.Now()
+	m := WithMeta("s", "str", "i", int64(10), "b", true, "d", time.Second, "t", now, "secret", Secret("x"))
+
+	if v, ok := m.GetString("s"); !ok || v != "str" {
+		t.Fatalf("GetString() expected str, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetInt("i"); !ok || v != 10 {
+		t.Fatalf("GetInt() expected 10, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetBool("b"); !ok || v != true {
+		t.Fatalf("GetBool() expected true, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetDuration("d"); !ok || v != time.Second {
+		t.Fatalf("GetDuration() expected 1s, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetTime("t"); !ok || !v.Equal(now) {
+		t.Fatalf("GetTime() expected %v, got: %v, %v", now, v, ok)
+	}
+
+	if v, ok := m.GetString("secret"); !ok || v != "x" {
+		t.Fatalf("GetString() expected the unwrapped secret value, got: %v, %v", v, ok)
+	}
+
+	if _, ok := m.GetString("missing"); ok {
+		t.Fatalf("GetString() expected false for a missing key")
+	}
+}
+
+func typedGettersFromJSON(t *testing.T) {
+	now := time.Now().UTC()
+	b, _ := json.Marshal(WithMeta("i", 10, "d", 2*time.Second, "t", now, "ds", "1m"))
+
+	var m Meta
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("expected json unmarshal nil error, got: %s", err.Error())
+	}
+
+	if v, ok := m.GetInt("i"); !ok || v != 10 {
+		t.Fatalf("GetInt() expected 10 from float64, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetDuration("d"); !ok || v != 2*time.Second {
+		t.Fatalf("GetDuration() expected 2s from float64, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetDuration("ds"); !ok || v != time.Minute {
+		t.Fatalf("GetDuration() expected 1m from string, got: %v, %v", v, ok)
+	}
+
+	if v, ok := m.GetTime("t"); !ok || !v.Equal(now) {
+		t.Fatalf("GetTime() expected %v from string, got: %v, %v", now, v, ok)
+	}
+}
+
+func typedGettersWrongType(t *testing.T) {
+	m := WithMeta("s", "str", "f", 1.5, "i", 10)
+
+	if _, ok := m.GetInt("s"); ok {
+		t.Fatalf("GetInt() expected false for a string")
+	}
+
+	if _, ok := m.GetInt("f"); ok {
+		t.Fatalf("GetInt() expected false for a non integral float")
+	}
+
+	if _, ok := m.GetBool("i"); ok {
+		t.Fatalf("GetBool() expected false for an int")
+	}
+
+	if _, ok := m.GetTime("s"); ok {
+		t.Fatalf("GetTime() expected false for an invalid time string")
+	}
+}
@@ -0,0 +1,115 @@
+package errors
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strings"
+)
+
+// joinError groups the causes of an Error when more than one error is passed to E or M.
+type joinError struct {
+	errs []error
+}
+
+// Error returns the messages of the causes separated by new lines, the same as the stdlib errors.Join.
+func (j *joinError) Error() string {
+	msgs := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the causes.
+func (j *joinError) Unwrap() []error {
+	return j.errs
+}
+
+// stdJoinErrorType is the type returned by the stdlib errors.Join.
+var stdJoinErrorType = reflect.TypeOf(stderrors.Join(stderrors.New("")))
+
+// Walk calls fn for every error in err's tree in depth-first order starting with err at depth 0. Errors only grouping
+// multiple causes, like the ones returned by the stdlib errors.Join, are not passed to fn, their causes are walked at
+// the same depth instead. Walk stops when fn returns false.
+func Walk(err error, fn func(err error, depth int) bool) {
+	walk(err, 0, fn)
+}
+
+func walk(err error, depth int, fn func(err error, depth int) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if isJoin(err) {
+		for _, c := range childrenOf(err) {
+			if !walk(c, depth, fn) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !fn(err, depth) {
+		return false
+	}
+
+	for _, c := range childrenOf(err) {
+		if !walk(c, depth+1, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// childrenOf returns the errors directly wrapped by err, the causes of grouping errors are returned in place of the
+// grouping error itself.
+func childrenOf(err error) (ret []error) {
+	for _, c := range causesOf(err) {
+		if c == nil {
+			continue
+		}
+
+		if isJoin(c) {
+			ret = append(ret, childrenOf(c)...)
+			continue
+		}
+
+		ret = append(ret, c)
+	}
+
+	return
+}
+
+// causesOf returns the errors directly wrapped by err.
+func causesOf(err error) []error {
+	switch e := err.(type) {
+	case *Error:
+		if e.err == nil {
+			return nil
+		}
+
+		return []error{e.err}
+
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+
+	case interface{ Unwrap() error }:
+		if u := e.Unwrap(); u != nil {
+			return []error{u}
+		}
+	}
+
+	return nil
+}
+
+// isJoin reports whether err only groups multiple causes without adding any information on its own.
+func isJoin(err error) bool {
+	if _, ok := err.(*joinError); ok {
+		return true
+	}
+
+	return reflect.TypeOf(err) == stdJoinErrorType
+}
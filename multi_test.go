@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMultiErrors(t *testing.T) {
+	t.Run("it should store multiple causes", storeMultipleCauses)
+	t.Run("it should flatten every branch", flattenEveryBranch)
+	t.Run("it should flatten stdlib joined errors", flattenStdJoin)
+	t.Run("it should match messages in every branch", matchEveryBranch)
+	t.Run("it should match Is and As in every branch", isAsEveryBranch)
+	t.Run("it should walk the tree with depth", walkWithDepth)
+	t.Run("it should stop walking when fn returns false", walkStops)
+	t.Run("it should output every branch", outputEveryBranch)
+	t.Run("it should pretty print every root of a stdlib join", prettyPrintStdJoin)
+}
+
+func storeMultipleCauses(t *testing.T) {
+	c1 := E("cause 1")
+	c2 := errors.New("cause 2")
+
+	err := E("multi", c1, c2)
+
+	u, ok := Unwrap(err).(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected Unwrap() to return an error implementing Unwrap() []error, got: %T", Unwrap(err))
+	}
+
+	if causes := u.Unwrap(); len(causes) != 2 || causes[0] != c1 || causes[1] != c2 {
+		t.Fatalf("expected both causes, got: %+v", causes)
+	}
+
+	if Unwrap(E("single", c1)) != c1 {
+		t.Fatalf("expected a single cause to be unwrapped directly")
+	}
+}
+
+func flattenEveryBranch(t *testing.T) {
+	err := E("root", E("a", E("a1")), E("b", errors.New("b1")))
+
+	msgs := flattenMsgs(Flatten(err))
+	if msgs != "root,a,a1,b,b1" {
+		t.Fatalf("expected depth-first order root,a,a1,b,b1, got: %s", msgs)
+	}
+}
+
+func flattenStdJoin(t *testing.T) {
+	err := E("root", errors.Join(E("a"), errors.New("b")))
+
+	msgs := flattenMsgs(Flatten(err))
+	if msgs != "root,a,b" {
+		t.Fatalf("expected joined errors to be flattened without the join, got: %s", msgs)
+	}
+}
+
+func matchEveryBranch(t *testing.T) {
+	err := E("root", E("first branch"), errors.Join(errors.New("x"), E("second branch")))
+
+	if !HasMessage(err, "second branch") {
+		t.Fatalf("expected HasMessage() to match the second branch")
+	}
+
+	if !ContainsMessage(err, "first") {
+		t.Fatalf("expected ContainsMessage() to match the first branch")
+	}
+}
+
+func isAsEveryBranch(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	err := E("root", E("a"), E("b", sentinel, NotFound))
+
+	if !Is(err, sentinel) {
+		t.Fatalf("expected Is() to match the sentinel in the second branch")
+	}
+
+	if !Is(err, NotFound) {
+		t.Fatalf("expected Is() to match the kind in the second branch")
+	}
+
+	if KindOf(err) != NotFound {
+		t.Fatalf("expected KindOf() to find the kind in the second branch, got: %s", KindOf(err))
+	}
+}
+
+func walkWithDepth(t *testing.T) {
+	err := E("root", E("a", E("a1")), errors.Join(E("b")))
+
+	var got []string
+	Walk(err, func(wErr error, depth int) bool {
+		got = append(got, strings.Repeat("-", depth)+wErr.Error())
+		return true
+	})
+
+	if strings.Join(got, ",") != "root,-a,--a1,-b" {
+		t.Fatalf("expected root,-a,--a1,-b, got: %s", strings.Join(got, ","))
+	}
+}
+
+func walkStops(t *testing.T) {
+	err := E("root", E("a"), E("b"))
+
+	var n int
+	Walk(err, func(wErr error, _ int) bool {
+		n++
+		return wErr.Error() != "a"
+	})
+
+	if n != 2 {
+		t.Fatalf("expected the walk to stop after 2 errors, got: %d", n)
+	}
+}
+
+func outputEveryBranch(t *testing.T) {
+	err := E("root", E("a"), E("b"))
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", jerr.Error())
+	}
+
+	var cmp []Error
+	_ = json.Unmarshal(b, &cmp)
+
+	if len(cmp) != 3 || cmp[2].Msg != "b" {
+		t.Fatalf("expected every branch in the json payload, got: %s", string(b))
+	}
+
+	if out := PrettyPrint(err); !strings.Contains(out, "\na\n") || !strings.Contains(out, "\nb\n") {
+		t.Fatalf("expected every branch in PrettyPrint output, got: %s", out)
+	}
+}
+
+func prettyPrintStdJoin(t *testing.T) {
+	out := PrettyPrint(errors.Join(E("a"), E("b")))
+	if !strings.HasPrefix(out, "\na\n") || !strings.Contains(out, "\nb\n") {
+		t.Fatalf("expected every root in PrettyPrint output, got: %s", out)
+	}
+
+	out = PrettyPrint(fmt.Errorf("outer: %w", E("inner")))
+	if !strings.HasPrefix(out, "\nouter: inner\n") || !strings.Contains(out, "\ninner\n") {
+		t.Fatalf("expected the outer layer in PrettyPrint output, got: %s", out)
+	}
+}
+
+func flattenMsgs(errs []Error) string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Msg
+	}
+
+	return strings.Join(msgs, ",")
+}
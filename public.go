@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"encoding/json"
+)
+
+// DefaultPublicMessage is returned by PublicMessage when none of the errors in the chain has a public message.
+const DefaultPublicMessage = "an unexpected error occurred"
+
+// Public is a message safe to show to the users of an application, ex. in API responses. Pass it to E, M or Define to
+// set the PublicMsg of the error, the Msg is still used for logs.
+//
+//	errors.E("select users: pq: relation \"users\" does not exist", errors.Public("cannot load the users"))
+type Public string
+
+// Audience selects the fields encoded by MarshalJSONFor.
+type Audience uint8
+
+// Audiences.
+const (
+	AudienceInternal Audience = iota // Encode the whole chain with every field, the same as MarshalJSON.
+	AudiencePublic                   // Encode only the public message, the kind and the code of the error.
+)
+
+// jsonPublicError is the JSON shape of an error for AudiencePublic.
+type jsonPublicError struct {
+	Msg  string `json:"msg"`
+	Kind Kind   `json:"kind,omitempty"`
+	Code Code   `json:"code,omitempty"`
+}
+
+// LookupPublicMessage returns the outermost public message in err's chain. The second returned argument is FALSE if
+// none of the errors have a public message.
+func LookupPublicMessage(err error) (msg string, found bool) {
+	Walk(err, func(wErr error, _ int) bool {
+		e, ok := wErr.(*Error)
+		if !ok {
+			return true
+		}
+
+		msg, found = e.PublicMsg, len(e.PublicMsg) > 0
+
+		return !found
+	})
+
+	return
+}
+
+// PublicMessage returns the outermost public message in err's chain or DefaultPublicMessage if none of the errors have
+// a public message, so internal messages are never returned.
+func PublicMessage(err error) string {
+	if msg, ok := LookupPublicMessage(err); ok {
+		return msg
+	}
+
+	return DefaultPublicMessage
+}
+
+// MarshalJSONFor encodes err for the given audience. AudienceInternal has the same output as Error MarshalJSON, regular
+// errors are converted to Error. AudiencePublic encodes a single object with the message returned by PublicMessage and
+// the outermost kind and code of the chain, see KindOf and CodeOf. A nil err is encoded as null.
+func MarshalJSONFor(err error, audience Audience) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	if audience == AudiencePublic {
+		return json.Marshal(jsonPublicError{Msg: PublicMessage(err), Kind: KindOf(err), Code: CodeOf(err)})
+	}
+
+	return marshalJSONTree(err)
+}
@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPublicMessage(t *testing.T) {
+	t.Run("it should set the public message", setPublicMessage)
+	t.Run("it should find the outermost public message", findPublicMessage)
+	t.Run("it should fall back to a generic message", fallbackPublicMessage)
+	t.Run("it should encode for the internal audience", encodeInternalAudience)
+	t.Run("it should encode for the public audience", encodePublicAudience)
+}
+
+func setPublicMessage(t *testing.T) {
+	err := E("select users: timeout", Public("cannot load the users"))
+
+	var e *Error
+	As(err, &e)
+
+	if e.Msg != "select users: timeout" || e.PublicMsg != "cannot load the users" {
+		t.Fatalf("expected both messages to be set, got: %s / %s", e.Msg, e.PublicMsg)
+	}
+
+	As(M(err), &e)
+	if e.PublicMsg != "cannot load the users" {
+		t.Fatalf("expected M to keep the public message, got: %s", e.PublicMsg)
+	}
+
+	tmpl := Define("user.not_found", "user row missing", Public("user not found"))
+
+	As(tmpl.New(), &e)
+	if e.PublicMsg != "user not found" {
+		t.Fatalf("expected the template public message, got: %s", e.PublicMsg)
+	}
+}
+
+func findPublicMessage(t *testing.T) {
+	err := E("handler failed", E("service failed", Public("inner"), E("db failed", Public("innermost"))))
+
+	if msg := PublicMessage(err); msg != "inner" {
+		t.Fatalf("expected the outermost public message, got: %s", msg)
+	}
+
+	if _, ok := LookupPublicMessage(E("test")); ok {
+		t.Fatalf("expected no public message")
+	}
+}
+
+func fallbackPublicMessage(t *testing.T) {
+	if msg := PublicMessage(errors.New("pq: syntax error")); msg != DefaultPublicMessage {
+		t.Fatalf("expected the default public message, got: %s", msg)
+	}
+}
+
+func encodeInternalAudience(t *testing.T) {
+	err := E("outer", Public("public"), E("inner"))
+
+	b, jerr := MarshalJSONFor(err, AudienceInternal)
+	if jerr != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", jerr.Error())
+	}
+
+	cmp, _ := json.Marshal(err)
+	if string(b) != string(cmp) {
+		t.Fatalf("expected the MarshalJSON output, got: %s", string(b))
+	}
+
+	if !strings.Contains(string(b), `"publicMsg":"public"`) {
+		t.Fatalf("expected the public message in the output, got: %s", string(b))
+	}
+
+	if b, _ = MarshalJSONFor(errors.New("std"), AudienceInternal); string(b) != `{"msg":"std"}` {
+		t.Fatalf("expected the regular error to be converted, got: %s", string(b))
+	}
+}
+
+func encodePublicAudience(t *testing.T) {
+	err := E("outer", WithMeta("query", "select"), E("inner", NotFound, Code("user.not_found"), Public("user not found")))
+
+	b, _ := MarshalJSONFor(err, AudiencePublic)
+
+	want := `{"msg":"user not found","kind":"not_found","code":"user.not_found"}`
+	if string(b) != want {
+		t.Fatalf("expected %s, got: %s", want, string(b))
+	}
+
+	if b, _ = MarshalJSONFor(nil, AudiencePublic); string(b) != "null" {
+		t.Fatalf("expected null, got: %s", string(b))
+	}
+}
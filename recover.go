@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// MetaKeyPanic is the Meta key holding the recovered panic value, see Recover.
+const MetaKeyPanic = "panic"
+
+// Recover converts a panic into an error stored in errp, it must be called directly with defer:
+//
+//	func handle() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+//
+// The error has the Internal kind, the Source set to the frame which panicked, the stack of the panic and the panic
+// value in Meta under MetaKeyPanic. If the panic value is an error it is wrapped, so Is and As match it. The error
+// stored in errp is overwritten. Recover does nothing if there is no panic.
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	e := panicError(r)
+
+	if errp != nil {
+		*errp = e
+	}
+}
+
+// Go runs fn in a new goroutine and sends its result on the returned channel, a panic in fn is converted to an error
+// with Recover. Exactly one value is sent, nil if fn succeeded, then the channel is closed.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		var err error
+
+		defer func() {
+			ch <- err
+			close(ch)
+		}()
+
+		defer Recover(&err)
+
+		err = fn()
+	}()
+
+	return ch
+}
+
+// panicError returns the error for the recovered panic value r, it must be called by Recover.
+func panicError(r any) *Error {
+	e := &Error{}
+	e.Msg = fmt.Sprintf("panic: %v", r)
+	e.Kind = Internal
+	e.Meta = Meta{MetaKeyPanic: r}
+	e.Stack = panicStack()
+
+	if len(e.Stack) > 0 {
+		e.Source = Source{pc: uintptr(e.Stack[0])}
+	}
+
+	if err, ok := r.(error); ok {
+		e.err = err
+	}
+
+	runHooks(e)
+
+	return e
+}
+
+// panicStack returns the stack starting at the frame which panicked. The frames up to runtime.gopanic belong to the
+// deferred calls, the runtime frames right after it are the runtime functions raising the panic, ex. runtime.panicIndex.
+func panicStack() StackTrace {
+	// Skip runtime.Callers, panicStack and panicError.
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+
+	start := -1
+
+	for i := 0; i < n && start < 0; i++ {
+		for _, f := range symbolize(pcs[i]) {
+			if f.function == "runtime.gopanic" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	if start < 0 {
+		return nil
+	}
+
+	for start < n-1 && strings.HasPrefix(symbolize(pcs[start])[0].function, "runtime.") {
+		start++
+	}
+
+	st := make(StackTrace, n-start)
+	for i := start; i < n; i++ {
+		st[i-start] = Frame(pcs[i])
+	}
+
+	return st
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("it should convert a panic into an error", recoverPanic)
+	t.Run("it should set the source to the panicking frame", recoverPanicSource)
+	t.Run("it should skip the runtime frames of runtime errors", recoverRuntimeError)
+	t.Run("it should wrap error panic values", recoverErrorPanic)
+	t.Run("it should keep the error without a panic", recoverNoPanic)
+	t.Run("it should recover panics in Go", recoverInGo)
+}
+
+var errTestPanic = errors.New("test panic")
+
+// panicLine panics and returns the line of the panic through line.
+func panicLine(line *int, v any) (err error) {
+	defer Recover(&err)
+
+	_, _, *line, _ = runtime.Caller(0)
+	panic(v)
+}
+
+func recoverPanic(t *testing.T) {
+	var line int
+	err := panicLine(&line, "boom")
+
+	if err.Error() != "panic: boom" {
+		t.Fatalf("expected the panic message, got: %s", err.Error())
+	}
+
+	if !Is(err, Internal) {
+		t.Fatalf("expected the internal kind")
+	}
+
+	if m, _ := GetMeta(err); m[MetaKeyPanic] != "boom" {
+		t.Fatalf("expected the panic value in meta, got: %+v", m)
+	}
+}
+
+func recoverPanicSource(t *testing.T) {
+	var line int
+	err := panicLine(&line, "boom")
+
+	var e *Error
+	As(err, &e)
+
+	if e.Source.Line() != line+1 || e.Source.Function() != "github.com/primalskill/errors.panicLine" {
+		t.Fatalf("expected the source to be the panic, got: %s %s", e.Source, e.Source.Function())
+	}
+
+	if len(e.Stack) == 0 || e.Stack[0].line() != line+1 {
+		t.Fatalf("expected the stack to start at the panic, got: %+v", e.Stack)
+	}
+}
+
+func recoverRuntimeError(t *testing.T) {
+	var line int
+
+	err := func() (err error) {
+		defer Recover(&err)
+
+		var m map[string]int
+		_, _, line, _ = runtime.Caller(0)
+		m["key"]++
+
+		return nil
+	}()
+
+	var e *Error
+	As(err, &e)
+
+	if e.Source.Line() != line+1 {
+		t.Fatalf("expected the source to be the nil map write, got: %s %s", e.Source, e.Source.Function())
+	}
+
+	var re runtime.Error
+	if !As(err, &re) {
+		t.Fatalf("expected the runtime error to be wrapped")
+	}
+}
+
+func recoverErrorPanic(t *testing.T) {
+	var line int
+	err := panicLine(&line, errTestPanic)
+
+	if !Is(err, errTestPanic) {
+		t.Fatalf("expected the panic error to be wrapped")
+	}
+}
+
+func recoverNoPanic(t *testing.T) {
+	err := func() (err error) {
+		defer Recover(&err)
+		return E("test")
+	}()
+
+	if err.Error() != "test" {
+		t.Fatalf("expected the returned error, got: %s", err.Error())
+	}
+}
+
+func recoverInGo(t *testing.T) {
+	err := <-Go(func() error {
+		panic("boom")
+	})
+
+	if !Is(err, Internal) || !HasMessage(err, "panic: boom") {
+		t.Fatalf("expected the recovered panic, got: %v", err)
+	}
+
+	if err = <-Go(func() error { return nil }); err != nil {
+		t.Fatalf("expected nil error, got: %s", err.Error())
+	}
+
+	ch := Go(func() error { return E("test") })
+	if err = <-ch; err == nil || err.Error() != "test" {
+		t.Fatalf("expected the returned error, got: %v", err)
+	}
+
+	if _, open := <-ch; open {
+		t.Fatalf("expected the channel to be closed")
+	}
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive Meta values in every output.
+const RedactedValue = "[REDACTED]"
+
+// redaction holds the global redaction settings.
+var redaction = struct {
+	mu         sync.RWMutex
+	patterns   []string
+	unredacted bool
+}{}
+
+// SecretValue wraps a sensitive Meta value, see Secret.
+type SecretValue struct {
+	value any
+}
+
+// Secret wraps v so it's masked in every output regardless of its Meta key, ex. WithMeta("email", Secret(email)).
+func Secret(v any) SecretValue {
+	return SecretValue{value: v}
+}
+
+// Value returns the wrapped value.
+func (s SecretValue) Value() any {
+	return s.value
+}
+
+// String returns RedactedValue and satisfies the fmt.Stringer interface, so the wrapped value is never printed.
+func (s SecretValue) String() string {
+	return RedactedValue
+}
+
+// GoString returns the quoted RedactedValue and satisfies the fmt.GoStringer interface, so %#v never prints the wrapped
+// value.
+func (s SecretValue) GoString() string {
+	return strconv.Quote(RedactedValue)
+}
+
+// MarshalText implements encoding.TextMarshaler, the wrapped value is never encoded.
+func (s SecretValue) MarshalText() ([]byte, error) {
+	return []byte(RedactedValue), nil
+}
+
+// RedactKeys registers Meta key patterns whose values are masked in every output. Patterns are matched
+// case-insensitively with path.Match, ex. "password" or "*token*".
+func RedactKeys(patterns ...string) {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	for _, p := range patterns {
+		redaction.patterns = append(redaction.patterns, strings.ToLower(p))
+	}
+}
+
+// SetUnredacted disables or enables the redaction of sensitive Meta values in every output. It's meant for local
+// development only, never enable it in production.
+func SetUnredacted(unredacted bool) {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.unredacted = unredacted
+}
+
+// IsSensitiveKey reports whether key matches any of the patterns registered with RedactKeys.
+func IsSensitiveKey(key string) bool {
+	redaction.mu.RLock()
+	defer redaction.mu.RUnlock()
+
+	return isSensitiveKey(key)
+}
+
+// isSensitiveKey must be called with the redaction lock held.
+func isSensitiveKey(key string) bool {
+	if len(redaction.patterns) == 0 {
+		return false
+	}
+
+	key = strings.ToLower(key)
+
+	for _, p := range redaction.patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redacted returns p with the sensitive values replaced by RedactedValue. Values are sensitive if they are wrapped by
+// Secret or their key matches a RedactKeys pattern. In unredacted mode the Secret values are unwrapped instead. p is
+// returned as is if it doesn't have any sensitive values, otherwise a copy is returned.
+func (p Meta) Redacted() Meta {
+	redaction.mu.RLock()
+	defer redaction.mu.RUnlock()
+
+	sensitive := false
+	for k, v := range p {
+		if _, is := v.(SecretValue); is || (!redaction.unredacted && isSensitiveKey(k)) {
+			sensitive = true
+			break
+		}
+	}
+
+	if !sensitive {
+		return p
+	}
+
+	m := make(Meta, len(p))
+	for k, v := range p {
+		s, isSecret := v.(SecretValue)
+
+		switch {
+		case redaction.unredacted && isSecret:
+			m[k] = s.value
+		case redaction.unredacted:
+			m[k] = v
+		case isSecret || isSensitiveKey(k):
+			m[k] = RedactedValue
+		default:
+			m[k] = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	t.Run("it should mask Secret values", maskSecretValues)
+	t.Run("it should mask registered keys", maskRegisteredKeys)
+	t.Run("it should not copy Meta without sensitive values", redactedReturnsSameMeta)
+	t.Run("it should mask values in every output", maskEveryOutput)
+	t.Run("it should mask values in Go-syntax", maskGoSyntax)
+	t.Run("it should reveal values in unredacted mode", revealUnredacted)
+}
+
+func resetRedaction() {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.patterns = nil
+	redaction.unredacted = false
+}
+
+func maskSecretValues(t *testing.T) {
+	m := WithMeta("email", Secret("john@example.com"), "userId", 10)
+	r := m.Redacted()
+
+	if r["email"] != RedactedValue || r["userId"] != 10 {
+		t.Fatalf("expected only the secret value to be masked, got: %+v", r)
+	}
+
+	if s, ok := m["email"].(SecretValue); !ok || s.Value() != "john@example.com" {
+		t.Fatalf("expected the original Meta to be unchanged, got: %+v", m["email"])
+	}
+
+	if s := fmt.Sprintf("%v", Secret("x")); s != RedactedValue {
+		t.Fatalf("expected Secret to print as %s, got: %s", RedactedValue, s)
+	}
+}
+
+func maskRegisteredKeys(t *testing.T) {
+	defer resetRedaction()
+
+	RedactKeys("password", "*token*")
+
+	r := WithMeta("Password", "p4ss", "accessToken", "abc", "user", "john").Redacted()
+
+	if r["Password"] != RedactedValue || r["accessToken"] != RedactedValue || r["user"] != "john" {
+		t.Fatalf("expected the registered keys to be masked case-insensitively, got: %+v", r)
+	}
+
+	if !IsSensitiveKey("REFRESH_TOKEN") || IsSensitiveKey("user") {
+		t.Fatalf("expected IsSensitiveKey() to match the registered patterns")
+	}
+}
+
+func maskGoSyntax(t *testing.T) {
+	defer resetRedaction()
+
+	RedactKeys("password")
+
+	if out := fmt.Sprintf("%#v", Secret("hunter2")); out != `"[REDACTED]"` {
+		t.Fatalf("expected the Secret to be masked, got: %s", out)
+	}
+
+	out := fmt.Sprintf("%#v", WithMeta("token", Secret("hunter2"), "password", "p4ss", "user", "john"))
+	if out != `errors.Meta{"password":"[REDACTED]", "token":"[REDACTED]", "user":"john"}` {
+		t.Fatalf("expected the Meta to be masked, got: %s", out)
+	}
+
+	if out = fmt.Sprintf("%#v", Meta(nil)); out != "errors.Meta(nil)" {
+		t.Fatalf("expected a nil Meta in Go-syntax, got: %s", out)
+	}
+}
+
+func redactedReturnsSameMeta(t *testing.T) {
+	m := WithMeta("user", "john")
+	r := m.Redacted()
+
+	r["added"] = true
+
+	if _, has := m["added"]; !has {
+		t.Fatalf("expected Redacted() to return the same Meta when nothing is sensitive")
+	}
+}
+
+func maskEveryOutput(t *testing.T) {
+	defer resetRedaction()
+
+	RedactKeys("token")
+
+	err := E("login failed", WithMeta("token", "t0k3n", "email", Secret("john@example.com")))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+
+	b, _ := json.Marshal(err)
+	m, _ := GetMeta(err)
+
+	outputs := map[string]string{
+		"json":        string(b),
+		"Meta.String": m.String(),
+		"PrettyPrint": PrettyPrint(err),
+		"PrettyTree":  PrettyTree(err, nil),
+		"%+v":         fmt.Sprintf("%+v", err),
+		"%#v":         fmt.Sprintf("%#v", err),
+		"slog":        buf.String(),
+	}
+
+	for name, out := range outputs {
+		if strings.Contains(out, "t0k3n") || strings.Contains(out, "john@example.com") {
+			t.Fatalf("expected %s output to be redacted, got: %s", name, out)
+		}
+
+		if !strings.Contains(out, RedactedValue) {
+			t.Fatalf("expected %s output to contain %s, got: %s", name, RedactedValue, out)
+		}
+	}
+}
+
+func revealUnredacted(t *testing.T) {
+	defer resetRedaction()
+
+	RedactKeys("token")
+	SetUnredacted(true)
+
+	err := E("login failed", WithMeta("token", "t0k3n", "email", Secret("john@example.com")))
+
+	b, _ := json.Marshal(err)
+	if !strings.Contains(string(b), "t0k3n") || !strings.Contains(string(b), "john@example.com") {
+		t.Fatalf("expected unredacted json output, got: %s", string(b))
+	}
+
+	if out := PrettyPrint(err); !strings.Contains(out, "john@example.com") {
+		t.Fatalf("expected unredacted PrettyPrint output, got: %s", out)
+	}
+}
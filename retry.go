@@ -0,0 +1,250 @@
+package errors
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// MetaKeyRetryAfter is the Meta key read by Retry to delay the next attempt. The value can be a time.Duration, a
+// time.Time, a number of seconds or a string holding either a number of seconds or a time.ParseDuration value.
+const MetaKeyRetryAfter = "retry_after"
+
+// Retryability marks an error as safe or unsafe to retry when passed to E or M, see Retryable and Permanent.
+type Retryability uint8
+
+// Retryability values, the zero value means the error is not marked.
+const (
+	retryRetryable Retryability = iota + 1
+	retryPermanent
+)
+
+// Retryable returns the marker setting the error as safe to retry.
+func Retryable() Retryability {
+	return retryRetryable
+}
+
+// Permanent returns the marker setting the error as unsafe to retry.
+func Permanent() Retryability {
+	return retryPermanent
+}
+
+// retryableErrnos are the system call errors considered transient.
+var retryableErrnos = map[syscall.Errno]struct{}{
+	syscall.ECONNRESET:   {},
+	syscall.ECONNREFUSED: {},
+	syscall.ECONNABORTED: {},
+	syscall.EPIPE:        {},
+	syscall.ETIMEDOUT:    {},
+	syscall.EAGAIN:       {},
+	syscall.EHOSTUNREACH: {},
+	syscall.ENETUNREACH:  {},
+}
+
+// IsRetryable reports whether err is safe to retry. The errors in err's chain are checked outermost first and the first
+// decision is returned:
+//   - an error marked with Retryable or Permanent returns the marker
+//   - an error with the Unavailable or Timeout kind is retryable
+//   - context.DeadlineExceeded, a net.Error timeout and transient syscall errors like ECONNRESET are retryable
+//   - context.Canceled is not retryable
+//
+// Errors without any of the above are not retryable.
+func IsRetryable(err error) bool {
+	retry, _ := retryWalk(err)
+
+	return retry
+}
+
+// retryWalk returns the decision of the outermost error in err's chain which decides it, see IsRetryable.
+func retryWalk(err error) (retry bool, decided bool) {
+	Walk(err, func(wErr error, _ int) bool {
+		retry, decided = retryDecision(wErr)
+
+		return !decided
+	})
+
+	return
+}
+
+// retryDecision returns whether err is retryable and whether err alone is enough to decide it.
+func retryDecision(err error) (retry bool, decided bool) {
+	switch e := err.(type) {
+	case *Error:
+		switch e.retry {
+		case retryRetryable:
+			return true, true
+		case retryPermanent:
+			return false, true
+		}
+
+		if e.Kind == Unavailable || e.Kind == Timeout {
+			return true, true
+		}
+
+		// A regular error converted with M is only kept in withFlag, which is not part of the chain walked.
+		if _, isError := e.withFlag.(*Error); e.withFlag != nil && !isError {
+			return retryWalk(e.withFlag)
+		}
+
+		return false, false
+
+	case syscall.Errno:
+		_, retry = retryableErrnos[e]
+		return retry, retry
+
+	case net.Error:
+		if e.Timeout() {
+			return true, true
+		}
+
+		return false, false
+	}
+
+	switch err {
+	case context.DeadlineExceeded:
+		return true, true
+	case context.Canceled:
+		return false, true
+	}
+
+	return false, false
+}
+
+// RetryPolicy configures Retry. Zero fields are replaced with the DefaultRetryPolicy values, except Jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls including the first one.
+	MaxAttempts int
+
+	// InitialDelay is the delay after the first failed attempt, it's multiplied by Multiplier after every attempt.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the exponential backoff delay. A MetaKeyRetryAfter value is honoured even if it's higher.
+	MaxDelay time.Duration
+
+	// Multiplier is the backoff growth factor.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to the given fraction, ex. 0.2 means +/- 20%. Zero disables the jitter.
+	Jitter float64
+
+	// ShouldRetry reports whether an error returned by fn should be retried. It defaults to IsRetryable.
+	ShouldRetry func(err error) bool
+}
+
+// DefaultRetryPolicy is a policy with 3 attempts and an exponential backoff starting at 100ms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 100 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// Retry calls fn until it succeeds, it returns an error that's not retryable or policy.MaxAttempts is reached, in which
+// case the last error is returned. Between attempts Retry waits with an exponential backoff and jitter, or for the
+// MetaKeyRetryAfter value found in the error chain if it's longer.
+//
+// If ctx is done while waiting an error wrapping both the last error and the context cause is returned.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	p := policy.withDefaults()
+	delay := p.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= p.MaxAttempts || !p.ShouldRetry(err) {
+			return err
+		}
+
+		wait := p.jitter(delay)
+		if ra, ok := retryAfter(err); ok && ra > wait {
+			wait = ra
+		}
+
+		t := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return E("retry aborted", err, context.Cause(ctx))
+		case <-t.C:
+		}
+
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultRetryPolicy.InitialDelay
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = IsRetryable
+	}
+
+	return p
+}
+
+// jitter randomizes d by up to p.Jitter fraction in both directions.
+func (p RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+
+	delta := (rand.Float64()*2 - 1) * p.Jitter * float64(d) //nolint:gosec // jitter doesn't need a secure random number
+
+	return d + time.Duration(delta)
+}
+
+// retryAfter returns the outermost MetaKeyRetryAfter value in err's chain as a duration.
+func retryAfter(err error) (time.Duration, bool) {
+	v, has := LookupMeta(err, MetaKeyRetryAfter)
+	if !has {
+		return 0, false
+	}
+
+	switch v := v.(type) {
+	case time.Duration:
+		return v, true
+	case time.Time:
+		return time.Until(v), true
+	case int:
+		return time.Duration(v) * time.Second, true
+	case int64:
+		return time.Duration(v) * time.Second, true
+	case float64:
+		return time.Duration(v * float64(time.Second)), true
+	case string:
+		if secs, perr := strconv.ParseFloat(v, 64); perr == nil {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+
+		if d, perr := time.ParseDuration(v); perr == nil {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,188 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	t.Run("it should honour the retry markers", honourRetryMarkers)
+	t.Run("it should recognise transient stdlib errors", recogniseTransientErrors)
+	t.Run("it should classify errors converted with M", classifyMirroredErrors)
+	t.Run("it should not retry unknown errors", noRetryUnknownErrors)
+	t.Run("it should retry until success", retryUntilSuccess)
+	t.Run("it should stop on permanent errors", retryStopsOnPermanent)
+	t.Run("it should stop after max attempts", retryStopsAfterMaxAttempts)
+	t.Run("it should honour retry_after", retryHonoursRetryAfter)
+	t.Run("it should stop when the context is done", retryStopsOnContextDone)
+}
+
+var fastRetryPolicy = RetryPolicy{
+	MaxAttempts:  5,
+	InitialDelay: time.Millisecond,
+	MaxDelay:     5 * time.Millisecond,
+}
+
+func honourRetryMarkers(t *testing.T) {
+	if !IsRetryable(E("test", Retryable())) {
+		t.Fatalf("expected Retryable() error to be retryable")
+	}
+
+	if IsRetryable(E("test", Permanent(), Unavailable)) {
+		t.Fatalf("expected Permanent() to take precedence over the kind")
+	}
+
+	if IsRetryable(E("outer", Permanent(), E("inner", Retryable()))) {
+		t.Fatalf("expected the outermost marker to win")
+	}
+
+	if !IsRetryable(M(E("test", Retryable()))) {
+		t.Fatalf("expected M() to keep the marker")
+	}
+
+	if !IsRetryable(E("test", Timeout)) {
+		t.Fatalf("expected the Timeout kind to be retryable")
+	}
+}
+
+func recogniseTransientErrors(t *testing.T) {
+	opErr := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	cases := []error{
+		context.DeadlineExceeded,
+		E("wrapped", fmt.Errorf("ctx: %w", context.DeadlineExceeded)),
+		E("wrapped", opErr),
+		syscall.ECONNREFUSED,
+		&net.DNSError{Err: "timeout", IsTimeout: true},
+	}
+
+	for _, err := range cases {
+		if !IsRetryable(err) {
+			t.Fatalf("expected %v to be retryable", err)
+		}
+	}
+
+	if IsRetryable(E("wrapped", context.Canceled)) {
+		t.Fatalf("expected context.Canceled not to be retryable")
+	}
+
+	if IsRetryable(syscall.ENOENT) {
+		t.Fatalf("expected ENOENT not to be retryable")
+	}
+}
+
+func classifyMirroredErrors(t *testing.T) {
+	cases := []error{
+		M(syscall.ECONNRESET),
+		M(context.DeadlineExceeded, WithMeta("userId", 10)),
+		E("wrapped", M(fmt.Errorf("read: %w", syscall.ETIMEDOUT))),
+	}
+
+	for _, err := range cases {
+		if !IsRetryable(err) {
+			t.Fatalf("expected %v to be retryable", err)
+		}
+	}
+
+	if IsRetryable(M(context.Canceled)) || IsRetryable(M(syscall.ECONNRESET, Permanent())) {
+		t.Fatalf("expected context.Canceled and permanent errors not to be retryable")
+	}
+}
+
+func noRetryUnknownErrors(t *testing.T) {
+	if IsRetryable(E("test", errors.New("regular"))) || IsRetryable(nil) {
+		t.Fatalf("expected unknown errors not to be retryable")
+	}
+}
+
+func retryUntilSuccess(t *testing.T) {
+	var calls int
+
+	err := Retry(context.Background(), fastRetryPolicy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return E("flaky", Retryable())
+		}
+
+		return nil
+	})
+
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on the 3rd call, got: %v after %d calls", err, calls)
+	}
+}
+
+func retryStopsOnPermanent(t *testing.T) {
+	var calls int
+
+	err := Retry(context.Background(), fastRetryPolicy, func(context.Context) error {
+		calls++
+		return E("bad input", Invalid)
+	})
+
+	if calls != 1 || !HasMessage(err, "bad input") {
+		t.Fatalf("expected a single call returning the error, got: %v after %d calls", err, calls)
+	}
+}
+
+func retryStopsAfterMaxAttempts(t *testing.T) {
+	var calls int
+
+	err := Retry(context.Background(), fastRetryPolicy, func(context.Context) error {
+		calls++
+		return E("flaky", Retryable())
+	})
+
+	if calls != fastRetryPolicy.MaxAttempts || err == nil || err.Error() != "flaky" {
+		t.Fatalf("expected %d calls returning the last error, got: %v after %d calls", fastRetryPolicy.MaxAttempts, err, calls)
+	}
+}
+
+func retryHonoursRetryAfter(t *testing.T) {
+	var calls int
+	start := time.Now()
+
+	_ = Retry(context.Background(), RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond}, func(context.Context) error {
+		calls++
+		return E("rate limited", Retryable(), WithMeta(MetaKeyRetryAfter, "30ms"))
+	})
+
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected retry_after to delay the next attempt by 30ms, got: %s", elapsed)
+	}
+
+	if d, ok := retryAfter(E("x", WithMeta(MetaKeyRetryAfter, 2))); !ok || d != 2*time.Second {
+		t.Fatalf("expected numeric retry_after in seconds, got: %s", d)
+	}
+}
+
+func retryStopsOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cause := errors.New("shutting down")
+
+	err := Retry(ctx, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour}, func(context.Context) error {
+		time.AfterFunc(time.Millisecond, func() { cancel() })
+		return E("flaky", Retryable())
+	})
+
+	if !HasMessage(err, "flaky") || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the last error and the context error, got: %s", PrettyPrint(err))
+	}
+
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(cause)
+
+	err = Retry(ctx, RetryPolicy{MaxAttempts: 5}, func(context.Context) error {
+		return E("flaky", Retryable())
+	})
+
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected the context cause in the chain, got: %s", PrettyPrint(err))
+	}
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// defaultLogDepth is the number of wrapped errors included by LogValue and by SlogHandler when no depth is configured.
+const defaultLogDepth = 10
+
+// LogValue implements slog.LogValuer. The error is logged as a group containing the msg, kind, code, source and meta of
+// the error and the wrapped errors from Flatten under the "causes" group, keyed by their position in the chain. Messages
+// with Meta placeholders also have the template under "rawMsg", see Error.
+func (e *Error) LogValue() slog.Value {
+	return errorLogValue(e, defaultLogDepth)
+}
+
+// SlogHandlerOptions configures a SlogHandler.
+type SlogHandlerOptions struct {
+	// MaxDepth is the maximum number of wrapped errors logged for each error attribute. If it's zero or negative a
+	// default depth of 10 is used.
+	MaxDepth int
+}
+
+// SlogHandler wraps a slog.Handler and expands every error attribute in a record, including regular errors, to the
+// same group structure returned by Error.LogValue.
+type SlogHandler struct {
+	next     slog.Handler
+	maxDepth int
+}
+
+// NewSlogHandler returns a SlogHandler passing the expanded records to next. opts can be nil.
+func NewSlogHandler(next slog.Handler, opts *SlogHandlerOptions) *SlogHandler {
+	h := &SlogHandler{
+		next:     next,
+		maxDepth: defaultLogDepth,
+	}
+
+	if opts != nil && opts.MaxDepth > 0 {
+		h.maxDepth = opts.MaxDepth
+	}
+
+	return h
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands the error attributes of r and passes the record to the wrapped handler.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.expandAttr(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs returns a new SlogHandler whose wrapped handler has the expanded attrs.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = h.expandAttr(a)
+	}
+
+	return &SlogHandler{next: h.next.WithAttrs(expanded), maxDepth: h.maxDepth}
+}
+
+// WithGroup returns a new SlogHandler whose wrapped handler has the group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{next: h.next.WithGroup(name), maxDepth: h.maxDepth}
+}
+
+// expandAttr replaces error values with their expanded group, groups are expanded recursively.
+func (h *SlogHandler) expandAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = h.expandAttr(ga)
+		}
+
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			return slog.Attr{Key: a.Key, Value: errorLogValue(err, h.maxDepth)}
+		}
+	}
+
+	return a
+}
+
+// errorLogValue returns the group value of err including at most depth wrapped errors.
+func errorLogValue(err error, depth int) slog.Value {
+	errs := Flatten(err)
+	if len(errs) == 0 {
+		return slog.Value{}
+	}
+
+	attrs := errorLogAttrs(errs[0])
+
+	if len(errs) > 1 && depth > 0 {
+		causes := errs[1:]
+		if len(causes) > depth {
+			causes = causes[:depth]
+		}
+
+		causeAttrs := make([]slog.Attr, len(causes))
+		for i, c := range causes {
+			causeAttrs[i] = slog.Attr{Key: strconv.Itoa(i + 1), Value: slog.GroupValue(errorLogAttrs(c)...)}
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causeAttrs...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// errorLogAttrs returns the attributes of a single error, empty values are left out.
+func errorLogAttrs(e Error) []slog.Attr {
+	msg := e.message()
+	attrs := []slog.Attr{slog.String("msg", msg)}
+
+	// Keep the message template so the records can be grouped.
+	if msg != e.Msg {
+		attrs = append(attrs, slog.String("rawMsg", e.Msg))
+	}
+
+	if len(e.PublicMsg) > 0 {
+		attrs = append(attrs, slog.String("publicMsg", e.PublicMsg))
+	}
+
+	if e.Kind != Other {
+		attrs = append(attrs, slog.String("kind", e.Kind.String()))
+	}
+
+	if len(e.Code) > 0 {
+		attrs = append(attrs, slog.String("code", string(e.Code)))
+	}
+
+	if !e.Source.IsZero() {
+		attrs = append(attrs, slog.String("source", e.Source.String()))
+	}
+
+	if len(e.Meta) > 0 {
+		meta := e.Meta.Redacted()
+
+		metaAttrs := make([]slog.Attr, 0, len(meta))
+		for _, k := range meta.Keys() {
+			metaAttrs = append(metaAttrs, slog.Any(k, meta[k]))
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "meta", Value: slog.GroupValue(metaAttrs...)})
+	}
+
+	return attrs
+}
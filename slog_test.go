@@ -0,0 +1,138 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestSlog(t *testing.T) {
+	t.Run("it should log the error as a group", logErrorGroup)
+	t.Run("the handler should expand regular errors", handlerExpandsErrors)
+	t.Run("the handler should limit the depth", handlerLimitsDepth)
+	t.Run("the handler should expand attrs and groups", handlerExpandsAttrsAndGroups)
+	t.Run("it should log the message template", logMessageTemplate)
+}
+
+func logRecord(t *testing.T, h slog.Handler, log func(l *slog.Logger)) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	if h == nil {
+		h = slog.NewJSONHandler(&buf, nil)
+	} else if sh, ok := h.(*SlogHandler); ok {
+		sh.next = slog.NewJSONHandler(&buf, nil)
+	}
+
+	log(slog.New(h))
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("expected a json log record, got: %s", buf.String())
+	}
+
+	return rec
+}
+
+func logErrorGroup(t *testing.T) {
+	err := E("error 2", E("error 1", WithMeta("userId", 10)), NotFound, WithMeta("requestId", "abc"))
+
+	rec := logRecord(t, nil, func(l *slog.Logger) {
+		l.Error("failed", "err", err)
+	})
+
+	group, ok := rec["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected err to be a group, got: %+v", rec["err"])
+	}
+
+	if group["msg"] != "error 2" || group["kind"] != "not_found" || group["source"] == nil {
+		t.Fatalf("expected msg, kind and source in the group, got: %+v", group)
+	}
+
+	if meta, _ := group["meta"].(map[string]any); meta["requestId"] != "abc" {
+		t.Fatalf("expected meta in the group, got: %+v", group["meta"])
+	}
+
+	causes, _ := group["causes"].(map[string]any)
+	cause, _ := causes["1"].(map[string]any)
+
+	if cause["msg"] != "error 1" {
+		t.Fatalf("expected the wrapped error in causes, got: %+v", group["causes"])
+	}
+
+	if meta, _ := cause["meta"].(map[string]any); meta["userId"] != float64(10) {
+		t.Fatalf("expected the wrapped error meta, got: %+v", cause["meta"])
+	}
+}
+
+func handlerExpandsErrors(t *testing.T) {
+	err := fmt.Errorf("regular 2: %w", errors.New("regular 1"))
+
+	rec := logRecord(t, NewSlogHandler(nil, nil), func(l *slog.Logger) {
+		l.Error("failed", "err", err)
+	})
+
+	group, ok := rec["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected err to be expanded to a group, got: %+v", rec["err"])
+	}
+
+	if group["msg"] != "regular 2: regular 1" {
+		t.Fatalf("expected the error message in the group, got: %+v", group)
+	}
+
+	causes, _ := group["causes"].(map[string]any)
+	if cause, _ := causes["1"].(map[string]any); cause["msg"] != "regular 1" {
+		t.Fatalf("expected the wrapped error in causes, got: %+v", group["causes"])
+	}
+}
+
+func handlerLimitsDepth(t *testing.T) {
+	err := E("e3", E("e2", E("e1", E("e0"))))
+
+	rec := logRecord(t, NewSlogHandler(nil, &SlogHandlerOptions{MaxDepth: 2}), func(l *slog.Logger) {
+		l.Error("failed", "err", err)
+	})
+
+	group, _ := rec["err"].(map[string]any)
+	causes, _ := group["causes"].(map[string]any)
+
+	if len(causes) != 2 {
+		t.Fatalf("expected 2 causes, got: %+v", causes)
+	}
+}
+
+func handlerExpandsAttrsAndGroups(t *testing.T) {
+	err := errors.New("regular")
+
+	rec := logRecord(t, NewSlogHandler(nil, nil), func(l *slog.Logger) {
+		l.With("base", err).Error("failed", slog.Group("req", "err", err))
+	})
+
+	if _, ok := rec["base"].(map[string]any); !ok {
+		t.Fatalf("expected attrs passed to With to be expanded, got: %+v", rec["base"])
+	}
+
+	req, _ := rec["req"].(map[string]any)
+	if _, ok := req["err"].(map[string]any); !ok {
+		t.Fatalf("expected errors in groups to be expanded, got: %+v", rec["req"])
+	}
+}
+
+func logMessageTemplate(t *testing.T) {
+	err := E("user {userId} not found", TemplateMsg, WithMeta("userId", 10))
+
+	rec := logRecord(t, nil, func(l *slog.Logger) {
+		l.Error("failed", "err", err)
+	})
+
+	group, _ := rec["err"].(map[string]any)
+	if group["msg"] != "user 10 not found" || group["rawMsg"] != "user {userId} not found" {
+		t.Fatalf("expected the resolved message and the template, got: %+v", group)
+	}
+}
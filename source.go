@@ -1,45 +1,280 @@
 package errors
 
 import (
+	"path"
 	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
-// Source represents an error source capturing the file path and line number where the error happened in the format
-// <file path>:<line nunmber>. A Source is always attached to an error automatically.
-type Source string
-
-// getSource will get the file path, function name and line number where the error happened.
-func getSource() (s Source) {
-	// Index 3 will show the calling function data
-	targetFrameIndex := 3
-
-	// Set size to targetFrameIndex + 2 to ensure we have room for one more caller than we need.
-	programCounters := make([]uintptr, targetFrameIndex+2)
-	n := runtime.Callers(0, programCounters)
-
-	if n > 0 {
-		frames := runtime.CallersFrames(programCounters[:n])
-		for more, frameIndex := true, 0; more && frameIndex <= targetFrameIndex; frameIndex++ {
-			var frameCandidate runtime.Frame
-			frameCandidate, more = frames.Next()
-			if frameIndex == targetFrameIndex {
-				var sb strings.Builder
-
-				filePath := frameCandidate.File
-				if len(filePath) == 0 {
-					filePath = "unknown"
-				}
-
-				sb.WriteString(filePath)
-				sb.WriteString(":")
-				sb.WriteString(strconv.Itoa(frameCandidate.Line))
-
-				s = Source(sb.String())
+// Source represents an error source capturing the file path, line number and function where the error happened.
+// A Source is always attached to an error automatically. It's encoded as text in the format
+// <file path>:<line number>, the file path is trimmed to a module relative path, see SetSourceTrimming and
+// AddSourceRewrite.
+//
+// The source is recorded as a program counter and symbolized only when one of its methods is called, so errors which
+// are handled without being printed or encoded don't pay for the symbolization.
+type Source struct {
+	pc    uintptr
+	index int // index of the frame at pc, functions inlined at pc have multiple frames.
+
+	// Set for decoded sources only.
+	file     string
+	line     int
+	function string
+}
+
+// sourceRewrite replaces the prefix of a file path.
+type sourceRewrite struct {
+	prefix, replacement string
+}
+
+// sourceConfig holds the global source path settings.
+var sourceConfig = struct {
+	mu          sync.RWMutex
+	noTrimming  bool
+	rewrites    []sourceRewrite
+	modulesOnce sync.Once
+	mainModule  string
+	mainPackage string
+	modules     []string
+}{}
+
+// SetSourceTrimming enables or disables trimming the file paths of sources to module relative paths. It's enabled by
+// default. Files of the main module are relative to the module root, ex. internal/db/user.go, files of dependencies
+// are prefixed with the module path, ex. github.com/primalskill/errors/error.go, and files of the standard library are
+// prefixed with the package path, ex. runtime/proc.go. Files that don't belong to any known package are left as is.
+func SetSourceTrimming(enabled bool) {
+	sourceConfig.mu.Lock()
+	defer sourceConfig.mu.Unlock()
+
+	sourceConfig.noTrimming = !enabled
+	resetDisplayCache()
+}
+
+// AddSourceRewrite registers a rule replacing prefix with replacement in the file paths of sources. Rules are checked
+// in the order they were added and take precedence over the module trimming, the first matching rule is applied.
+func AddSourceRewrite(prefix, replacement string) {
+	sourceConfig.mu.Lock()
+	defer sourceConfig.mu.Unlock()
+
+	sourceConfig.rewrites = append(sourceConfig.rewrites, sourceRewrite{prefix: prefix, replacement: replacement})
+	resetDisplayCache()
+}
+
+// File returns the file path of the source with the trimming and rewrite rules applied.
+func (s Source) File() string {
+	// Decoded sources don't have a function and the file is already trimmed.
+	return s.frame().displayFile()
+}
+
+// FullPath returns the file path as recorded by the runtime, without trimming.
+func (s Source) FullPath() string {
+	return s.frame().file
+}
+
+// Line returns the line number of the source.
+func (s Source) Line() int {
+	return s.frame().line
+}
+
+// Function returns the fully qualified name of the function, ex. github.com/primalskill/errors.E. It's empty for
+// decoded sources.
+func (s Source) Function() string {
+	return s.frame().function
+}
+
+// Package returns the import path of the package of the function. It's empty for decoded sources.
+func (s Source) Package() string {
+	return packagePath(s.frame().function)
+}
+
+// IsZero reports whether s is the zero Source.
+func (s Source) IsZero() bool {
+	return s.pc == 0 && len(s.file) == 0 && s.line == 0 && len(s.function) == 0
+}
+
+// String returns the source in the format <file path>:<line number> and satisfies the fmt.Stringer interface. It
+// returns an empty string for the zero Source.
+func (s Source) String() string {
+	if s.IsZero() {
+		return ""
+	}
+
+	return s.File() + ":" + strconv.Itoa(s.Line())
+}
+
+// GoString returns the symbolized source in Go-syntax, satisfies the fmt.GoStringer interface.
+func (s Source) GoString() string {
+	return "errors.Source{File:" + strconv.Quote(s.File()) + ", Line:" + strconv.Itoa(s.Line()) + ", Function:" +
+		strconv.Quote(s.Function()) + "}"
+}
+
+// frame symbolizes the program counter of s, decoded sources return their own fields.
+func (s Source) frame() cachedFrame {
+	if s.pc == 0 {
+		return cachedFrame{file: s.file, line: s.line, function: s.function}
+	}
+
+	frames := symbolize(s.pc)
+	if s.index >= len(frames) {
+		return cachedFrame{file: "unknown"}
+	}
+
+	f := frames[s.index]
+	if len(f.file) == 0 {
+		f.file = "unknown"
+	}
+
+	return f
+}
+
+// MarshalText implements encoding.TextMarshaler, see String.
+func (s Source) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the format produced by MarshalText, if the line number
+// is missing the whole text is used as the file path.
+func (s *Source) UnmarshalText(b []byte) error {
+	*s = parseSource(string(b))
+	return nil
+}
+
+// parseSource parses a source in the format <file path>:<line number>.
+func parseSource(text string) (s Source) {
+	s.file = text
+
+	i := strings.LastIndexByte(text, ':')
+	if i < 0 {
+		return
+	}
+
+	line, err := strconv.Atoi(text[i+1:])
+	if err != nil {
+		return
+	}
+
+	s.file = text[:i]
+	s.line = line
+
+	return
+}
+
+// maxHelperDepth is the maximum number of helper frames skipped when attributing the source.
+const maxHelperDepth = 16
+
+// getSource will get the program counter of the function where the error happened. skip is the number of additional
+// frames to skip above the caller of E, M or New, the frames of helper functions are skipped too. The zero Source is
+// returned if the source isn't captured, see SetSourceCapture.
+func getSource(skip int) Source {
+	if !shouldCaptureSource() {
+		return Source{}
+	}
+
+	// Skip runtime.Callers, getSource and E, M or New to get to the calling function. Without helpers only the caller
+	// is needed and the symbolization is left to the Source methods.
+	var programCounters [maxHelperDepth]uintptr
+	if !helpers.active.Load() {
+		runtime.Callers(3+skip, programCounters[:1])
+		return Source{pc: programCounters[0]}
+	}
+
+	n := runtime.Callers(3+skip, programCounters[:])
+
+	var s Source
+
+	for _, pc := range programCounters[:n] {
+		for i, frame := range symbolize(pc) {
+			s = Source{pc: pc, index: i}
+
+			if !isHelper(frame.function) {
+				return s
 			}
 		}
 	}
 
-	return
+	// Every frame is a helper, use the outermost one.
+	return s
+}
+
+// displayPath applies the rewrite rules and the module trimming to file.
+func displayPath(file, function string) string {
+	sourceConfig.mu.RLock()
+	defer sourceConfig.mu.RUnlock()
+
+	for _, r := range sourceConfig.rewrites {
+		if strings.HasPrefix(file, r.prefix) {
+			return r.replacement + file[len(r.prefix):]
+		}
+	}
+
+	if sourceConfig.noTrimming {
+		return file
+	}
+
+	sourceConfig.modulesOnce.Do(loadModules)
+
+	pkg := strings.TrimSuffix(packagePath(function), "_test")
+	if pkg == "main" {
+		pkg = sourceConfig.mainPackage
+	}
+
+	for _, m := range sourceConfig.modules {
+		if pkg != m && !strings.HasPrefix(pkg, m+"/") {
+			continue
+		}
+
+		dir := strings.TrimPrefix(pkg[len(m):], "/")
+		if m != sourceConfig.mainModule {
+			dir = path.Join(m, dir)
+		}
+
+		return path.Join(dir, path.Base(file))
+	}
+
+	// Standard library packages don't belong to a module, their paths have no dot in the first element.
+	if first, _, _ := strings.Cut(pkg, "/"); len(pkg) > 0 && pkg != "main" && !strings.Contains(first, ".") {
+		return path.Join(pkg, path.Base(file))
+	}
+
+	return file
+}
+
+// loadModules reads the module paths from the build info, longest paths first so nested modules match first.
+func loadModules() {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	sourceConfig.mainModule = bi.Main.Path
+	sourceConfig.mainPackage = bi.Path
+
+	if len(bi.Main.Path) > 0 {
+		sourceConfig.modules = append(sourceConfig.modules, bi.Main.Path)
+	}
+
+	for _, d := range bi.Deps {
+		sourceConfig.modules = append(sourceConfig.modules, d.Path)
+	}
+
+	sort.Slice(sourceConfig.modules, func(i, j int) bool {
+		return len(sourceConfig.modules[i]) > len(sourceConfig.modules[j])
+	})
+}
+
+// packagePath returns the import path of the package from a fully qualified function name.
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+
+	if dot < 0 {
+		return function
+	}
+
+	return function[:slash+1+dot]
 }
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	t.Run("it should trim the path to the module root", trimSourceToModule)
+	t.Run("it should record the function and package", recordSourceFunction)
+	t.Run("it should keep the full path when trimming is disabled", disableSourceTrimming)
+	t.Run("it should apply the rewrite rules", rewriteSourcePath)
+	t.Run("it should encode and decode as text", sourceAsText)
+	t.Run("it should leave out the zero source in JSON", omitZeroSourceJSON)
+	t.Run("it should not capture the source when disabled", captureSourceNever)
+	t.Run("it should sample the source capture", captureSourceSampled)
+	t.Run("it should capture the source only in debug builds", captureSourceDebug)
+	t.Run("it should symbolize the source lazily", symbolizeSourceLazily)
+}
+
+func trimSourceToModule(t *testing.T) {
+	e := E("test").(*Error)
+	_, _, line, _ := runtime.Caller(0)
+
+	want := "source_test.go:" + strconv.Itoa(line-1)
+	if e.Source.String() != want {
+		t.Fatalf("expected source %s, got: %s", want, e.Source.String())
+	}
+
+	if !strings.HasSuffix(e.Source.FullPath(), "/source_test.go") || e.Source.FullPath() == e.Source.File() {
+		t.Fatalf("expected the full path to be untrimmed, got: %s", e.Source.FullPath())
+	}
+}
+
+func recordSourceFunction(t *testing.T) {
+	e := E("test").(*Error)
+
+	if e.Source.Function() != "github.com/primalskill/errors.recordSourceFunction" {
+		t.Fatalf("expected the caller function, got: %s", e.Source.Function())
+	}
+
+	if e.Source.Package() != "github.com/primalskill/errors" {
+		t.Fatalf("expected the caller package, got: %s", e.Source.Package())
+	}
+}
+
+func disableSourceTrimming(t *testing.T) {
+	SetSourceTrimming(false)
+	defer SetSourceTrimming(true)
+
+	e := E("test").(*Error)
+
+	if e.Source.File() != e.Source.FullPath() {
+		t.Fatalf("expected the full path, got: %s", e.Source.File())
+	}
+}
+
+func rewriteSourcePath(t *testing.T) {
+	e := E("test").(*Error)
+	prefix := strings.TrimSuffix(e.Source.FullPath(), "source_test.go")
+
+	AddSourceRewrite(prefix, "src/")
+	defer func() {
+		sourceConfig.mu.Lock()
+		sourceConfig.rewrites = nil
+		resetDisplayCache()
+		sourceConfig.mu.Unlock()
+	}()
+
+	if e.Source.File() != "src/source_test.go" {
+		t.Fatalf("expected the rewritten path, got: %s", e.Source.File())
+	}
+}
+
+func sourceAsText(t *testing.T) {
+	var s Source
+	_ = s.UnmarshalText([]byte("internal/db/user.go:42"))
+
+	if s.File() != "internal/db/user.go" || s.Line() != 42 {
+		t.Fatalf("expected file and line to be parsed, got: %s %d", s.File(), s.Line())
+	}
+
+	if b, _ := s.MarshalText(); string(b) != "internal/db/user.go:42" {
+		t.Fatalf("expected the same text, got: %s", string(b))
+	}
+
+	_ = s.UnmarshalText([]byte("unknown"))
+	if s.File() != "unknown" || s.Line() != 0 {
+		t.Fatalf("expected the text as file, got: %s %d", s.File(), s.Line())
+	}
+}
+
+func omitZeroSourceJSON(t *testing.T) {
+	b, _ := json.Marshal(&Error{Msg: "test"})
+
+	if strings.Contains(string(b), "source") {
+		t.Fatalf("expected the zero source to be left out, got: %s", string(b))
+	}
+}
+
+func captureSourceNever(t *testing.T) {
+	SetSourceCapture(CaptureNever)
+	defer SetSourceCapture(CaptureAlways)
+
+	e := E("test").(*Error)
+	if !e.Source.IsZero() {
+		t.Fatalf("expected the zero source, got: %s", e.Source)
+	}
+
+	if strings.Contains(PrettyPrint(e), "Source") {
+		t.Fatalf("expected the source to be left out, got: %s", PrettyPrint(e))
+	}
+}
+
+func captureSourceSampled(t *testing.T) {
+	SetSourceCapture(CaptureSampled)
+	SetSourceSampleRate(4)
+
+	defer func() {
+		SetSourceCapture(CaptureAlways)
+		SetSourceSampleRate(defaultSampleRate)
+	}()
+
+	captured := 0
+	for i := 0; i < 20; i++ {
+		if e := E("test").(*Error); !e.Source.IsZero() {
+			captured++
+		}
+	}
+
+	if captured != 5 {
+		t.Fatalf("expected 5 sampled sources, got: %d", captured)
+	}
+}
+
+func captureSourceDebug(t *testing.T) {
+	SetSourceCapture(CaptureDebug)
+	defer SetSourceCapture(CaptureAlways)
+
+	e := E("test").(*Error)
+	if e.Source.IsZero() == debugBuild {
+		t.Fatalf("expected the source to be captured only in debug builds, got: %s", e.Source)
+	}
+}
+
+func symbolizeSourceLazily(t *testing.T) {
+	e := E("test").(*Error)
+	_, _, line, _ := runtime.Caller(0)
+
+	if e.Source.pc == 0 || len(e.Source.file) > 0 || len(e.Source.function) > 0 {
+		t.Fatalf("expected only the program counter to be recorded, got: %d %s", e.Source.pc, e.Source.file)
+	}
+
+	if e.Source.Line() != line-1 || e.Source.Function() != "github.com/primalskill/errors.symbolizeSourceLazily" {
+		t.Fatalf("expected the source to be symbolized on demand, got: %s %s", e.Source, e.Source.Function())
+	}
+}
@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames recorded in a StackTrace.
+const maxStackDepth = 32
+
+// captureStackTraces is the global stack trace setting, see SetStackTrace.
+var captureStackTraces atomic.Bool
+
+// SetStackTrace enables or disables recording the full call stack on every error created by E and M. It's disabled by
+// default, use WithStack or WithoutStack to override it for a single call.
+func SetStackTrace(enabled bool) {
+	captureStackTraces.Store(enabled)
+}
+
+// StackMode passed to E or M overrides the global stack trace setting for that call.
+type StackMode uint8
+
+// Stack modes.
+const (
+	WithStack    StackMode = iota + 1 // Record the full call stack on the error.
+	WithoutStack                      // Don't record the call stack on the error.
+)
+
+// Frame is a single entry of a StackTrace holding the return address recorded by runtime.Callers. It has the same
+// representation as the frames of pkg/errors so tools reading those stack traces work with Error too.
+type Frame uintptr
+
+// frame symbolizes f using the frame cache. When functions were inlined at the address the innermost one is used, it
+// holds the line which was executing.
+func (f Frame) frame() cachedFrame {
+	frames := symbolize(uintptr(f))
+	if len(frames) == 0 || len(frames[0].function) == 0 {
+		return cachedFrame{file: "unknown", function: "unknown"}
+	}
+
+	return frames[0]
+}
+
+// file returns the path of the file with the trimming and rewrite rules applied, the same as Source.File.
+func (f Frame) file() string {
+	return f.frame().displayFile()
+}
+
+// line returns the line number in the file.
+func (f Frame) line() int {
+	return f.frame().line
+}
+
+// name returns the fully qualified function name or "unknown" if it can't be resolved.
+func (f Frame) name() string {
+	return f.frame().function
+}
+
+// Format implements fmt.Formatter with the verbs known from pkg/errors:
+//
+//	%s    base name of the file
+//	%d    line number
+//	%n    function name without the package path
+//	%v    the same as %s:%d
+//	%+s   fully qualified function name, a new line and a tab followed by the file path, trimmed like Source.File
+//	%+v   the same as %+s:%d
+func (f Frame) Format(s fmt.State, verb rune) {
+	fr := f.frame()
+
+	var out string
+
+	switch verb {
+	case 's', 'v':
+		out = path.Base(fr.file)
+		if s.Flag('+') {
+			out = fr.function + "\n\t" + fr.displayFile()
+		}
+
+		if verb == 'v' {
+			out += ":" + strconv.Itoa(fr.line)
+		}
+
+	case 'd':
+		out = strconv.Itoa(fr.line)
+
+	case 'n':
+		out = shortFuncName(fr.function)
+	}
+
+	_, _ = io.WriteString(s, out)
+}
+
+// MarshalText encodes the frame as <function name> <file path>:<line number>, or "unknown" if the frame can't be
+// resolved. The file path is trimmed like Source.File.
+func (f Frame) MarshalText() ([]byte, error) {
+	fr := f.frame()
+	if fr.function == "unknown" {
+		return []byte(fr.function), nil
+	}
+
+	return []byte(fr.function + " " + fr.displayFile() + ":" + strconv.Itoa(fr.line)), nil
+}
+
+// StackTrace holds the Frames of a call stack, the first Frame is the innermost call. Error exposes it with its
+// StackTrace method, the same method pkg/errors uses, so tools reading stack traces from errors work with Error too.
+type StackTrace []Frame
+
+// Format implements fmt.Formatter. %s and %v print the frames in brackets separated by spaces using the same verb and
+// flags for each Frame, ex. [error.go:12 main.go:8]. %+v prints every Frame with %+v on its own line and %#v prints the
+// frames in Go-syntax.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	if verb != 's' && verb != 'v' {
+		return
+	}
+
+	if verb == 'v' && s.Flag('#') {
+		fmt.Fprintf(s, "%#v", []Frame(st))
+		return
+	}
+
+	frameFormat := "%" + string(verb)
+	if s.Flag('+') {
+		frameFormat = "%+" + string(verb)
+	}
+
+	lines := verb == 'v' && s.Flag('+')
+
+	var b strings.Builder
+
+	if !lines {
+		b.WriteByte('[')
+	}
+
+	for i, f := range st {
+		switch {
+		case lines:
+			b.WriteByte('\n')
+		case i > 0:
+			b.WriteByte(' ')
+		}
+
+		fmt.Fprintf(&b, frameFormat, f)
+	}
+
+	if !lines {
+		b.WriteByte(']')
+	}
+
+	_, _ = io.WriteString(s, b.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Program counters are only valid inside the process that recorded them, so
+// a decoded StackTrace is always empty.
+func (st *StackTrace) UnmarshalJSON(_ []byte) error {
+	*st = nil
+	return nil
+}
+
+// StackTrace returns the call stack recorded on the error or nil if no stack was recorded.
+func (e *Error) StackTrace() StackTrace {
+	return e.Stack
+}
+
+// captureStack reports whether a stack trace should be recorded based on the global setting and the StackMode found in
+// args.
+func captureStack(args []any) bool {
+	capture := captureStackTraces.Load()
+
+	for _, arg := range args {
+		if m, ok := arg.(StackMode); ok {
+			capture = m == WithStack
+		}
+	}
+
+	return capture
+}
+
+// getStack records the call stack of the function calling E, M or New. skip is the number of additional frames to skip,
+// the leading frames of helper functions are skipped too.
+func getStack(skip int) StackTrace {
+	// Skip runtime.Callers, getStack and E, M or New.
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3+skip, pcs[:])
+
+	start := 0
+	for start < n-1 && isHelper(Frame(pcs[start]).name()) {
+		start++
+	}
+
+	st := make(StackTrace, n-start)
+	for i := start; i < n; i++ {
+		st[i-start] = Frame(pcs[i])
+	}
+
+	return st
+}
+
+// shortFuncName returns the function name without the package path, ex. (*Error).Error.
+func shortFuncName(name string) string {
+	return strings.TrimPrefix(name[len(packagePath(name)):], ".")
+}
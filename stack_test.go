@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	t.Run("it should not record the stack by default", noStackByDefault)
+	t.Run("it should record the stack with WithStack", recordStackWithStack)
+	t.Run("it should record the stack when enabled globally", recordStackGlobally)
+	t.Run("M() should keep the original stack", mirrorKeepsStack)
+	t.Run("it should format frames like pkg/errors", formatFrames)
+	t.Run("it should include the stack in json and PrettyPrint", stackInOutput)
+	t.Run("it should trim the file paths of frames", trimFramePaths)
+}
+
+func noStackByDefault(t *testing.T) {
+	var e *Error
+	As(E("test"), &e)
+
+	if e.StackTrace() != nil {
+		t.Fatalf("expected no stack trace, got: %v", e.StackTrace())
+	}
+}
+
+func recordStackWithStack(t *testing.T) {
+	var e *Error
+	As(E("test", WithStack), &e)
+
+	st := e.StackTrace()
+	if len(st) == 0 {
+		t.Fatalf("expected a stack trace")
+	}
+
+	if name := st[0].name(); !strings.HasSuffix(name, "recordStackWithStack") {
+		t.Fatalf("expected the first frame to be the caller of E(), got: %s", name)
+	}
+
+	if !strings.HasSuffix(e.Source.String(), fmt.Sprintf("stack_test.go:%d", st[0].line())) {
+		t.Fatalf("expected the first frame to match the source %s, got line: %d", e.Source, st[0].line())
+	}
+}
+
+func recordStackGlobally(t *testing.T) {
+	SetStackTrace(true)
+	defer SetStackTrace(false)
+
+	var e *Error
+	As(E("test"), &e)
+
+	if len(e.StackTrace()) == 0 {
+		t.Fatalf("expected a stack trace when enabled globally")
+	}
+
+	As(E("test", WithoutStack), &e)
+
+	if len(e.StackTrace()) != 0 {
+		t.Fatalf("expected WithoutStack to override the global setting")
+	}
+}
+
+func mirrorKeepsStack(t *testing.T) {
+	e1 := E("test", WithStack)
+	e2 := M(e1)
+
+	var ee1, ee2 *Error
+	As(e1, &ee1)
+	As(e2, &ee2)
+
+	if len(ee2.Stack) == 0 || ee2.Stack[0] != ee1.Stack[0] {
+		t.Fatalf("expected M() to keep the stack of the original error")
+	}
+
+	As(M(e1, WithStack), &ee2)
+
+	if ee2.Stack[0] == ee1.Stack[0] {
+		t.Fatalf("expected M() to record a new stack with WithStack")
+	}
+}
+
+func formatFrames(t *testing.T) {
+	var e *Error
+	As(E("test", WithStack), &e)
+
+	f := e.StackTrace()[0]
+
+	if s := fmt.Sprintf("%s", f); s != "stack_test.go" {
+		t.Fatalf("expected %%s to print the file name, got: %s", s)
+	}
+
+	if s := fmt.Sprintf("%n", f); s != "formatFrames" {
+		t.Fatalf("expected %%n to print the function name, got: %s", s)
+	}
+
+	if s := fmt.Sprintf("%+v", e.StackTrace()); !strings.Contains(s, "errors.formatFrames\n\t") {
+		t.Fatalf("expected %%+v to print the function and file, got: %s", s)
+	}
+}
+
+func stackInOutput(t *testing.T) {
+	err := E("test", WithStack)
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("expected json marshal nil error, got: %s", jerr.Error())
+	}
+
+	if !strings.Contains(string(b), `"stack":["github.com/primalskill/errors.stackInOutput `) {
+		t.Fatalf("expected the stack in the json payload, got: %s", string(b))
+	}
+
+	derr, jerr := FromJSON(b)
+	if jerr != nil || derr.Error() != "test" {
+		t.Fatalf("expected payload with stack to decode, got: %+v, %+v", derr, jerr)
+	}
+
+	if out := PrettyPrint(err); !strings.Contains(out, "|- Stack :") {
+		t.Fatalf("expected the stack in PrettyPrint output, got: %s", out)
+	}
+}
+
+func trimFramePaths(t *testing.T) {
+	e := E("test", WithStack).(*Error)
+
+	b, _ := json.Marshal(e)
+	if !strings.Contains(string(b), `errors.trimFramePaths stack_test.go:`) ||
+		!strings.Contains(string(b), `testing.tRunner testing/testing.go:`) {
+		t.Fatalf("expected module and package relative paths, got: %s", string(b))
+	}
+
+	for _, out := range []string{string(b), PrettyPrint(e), fmt.Sprintf("%+v", e.Stack)} {
+		if strings.Contains(out, e.Source.FullPath()) || strings.Contains(out, "/src/testing/") {
+			t.Fatalf("expected no absolute paths, got: %s", out)
+		}
+	}
+}
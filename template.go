@@ -0,0 +1,84 @@
+package errors
+
+// Template defines a class of errors, like a sentinel error, without creating the error itself. Declare templates as
+// package level variables with Define and create the errors with New, so the Source of each error points to where it
+// was created instead of the variable declaration.
+//
+// Template satisfies the stdlib error interface so it can be used as a target in Is, every error created from the
+// template matches it.
+type Template struct { //nolint:errname // used as an errors.Is target, like a sentinel error
+	proto Error
+}
+
+// Define returns a new Template with code and msg. Additional arguments like a Kind or a Meta map are set on every error
+// created from the template.
+//
+//	var ErrUserNotFound = errors.Define("user.not_found", "user not found", errors.NotFound)
+func Define(code string, msg string, args ...any) *Template {
+	t := &Template{}
+	t.proto.Msg = msg
+	t.proto.Code = Code(code)
+
+	// Errors can't be part of a template, only the settings are kept.
+	t.proto.parseArgTypes(args...)
+	t.proto.err = nil
+
+	return t
+}
+
+// New returns a new error from the template with the Source set to the caller. args are parsed the same way as in E,
+// a Meta map is merged with the template Meta.
+func (t *Template) New(args ...any) error {
+	e := &Error{}
+	e.Msg = t.proto.Msg
+	e.PublicMsg = t.proto.PublicMsg
+	e.Kind = t.proto.Kind
+	e.Code = t.proto.Code
+	e.retry = t.proto.retry
+	e.tmpl = t
+	e.tmplMsg = t.proto.tmplMsg
+	e.Source = getSource(0)
+
+	if captureStack(args) {
+		e.Stack = getStack(0)
+	}
+
+	// Copy the template Meta so the errors don't share the same map.
+	if len(t.proto.Meta) > 0 {
+		e.Meta = make(Meta, len(t.proto.Meta))
+
+		for k, v := range t.proto.Meta {
+			e.Meta[k] = v
+		}
+	}
+
+	e.parseArgTypes(args...)
+	runHooks(e)
+
+	return e
+}
+
+// Error returns the template message and satisfies the stdlib Error interface.
+func (t *Template) Error() string {
+	return t.proto.Msg
+}
+
+// Code returns the template code.
+func (t *Template) Code() Code {
+	return t.proto.Code
+}
+
+// Kind returns the template kind.
+func (t *Template) Kind() Kind {
+	return t.proto.Kind
+}
+
+// matchTemplate reports whether e was created from t. Errors decoded from JSON lose the reference to their template,
+// they match if they have the same non-empty code.
+func (e *Error) matchTemplate(t *Template) bool {
+	if e.tmpl != nil {
+		return e.tmpl == t
+	}
+
+	return e.decoded && len(t.proto.Code) > 0 && e.Code == t.proto.Code
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var errTestNotFound = Define("test.not_found", "test item not found", NotFound, WithMeta("resource", "item"))
+
+func TestTemplate(t *testing.T) {
+	t.Run("it should create errors from the template", createFromTemplate)
+	t.Run("it should set the source to the caller", templateSourceIsCaller)
+	t.Run("it should match the template with Is", matchTemplateWithIs)
+	t.Run("it should not share Meta between errors", templateMetaNotShared)
+	t.Run("it should match decoded errors by code", matchDecodedTemplate)
+}
+
+func createFromTemplate(t *testing.T) {
+	err := errTestNotFound.New(WithMeta("id", 10))
+
+	var e *Error
+	As(err, &e)
+
+	if e.Msg != "test item not found" || e.Code != "test.not_found" || e.Kind != NotFound {
+		t.Fatalf("expected the template msg, code and kind, got: %s, %s, %s", e.Msg, e.Code, e.Kind)
+	}
+
+	if e.Meta["resource"] != "item" || e.Meta["id"] != 10 {
+		t.Fatalf("expected the template Meta merged with the args, got: %+v", e.Meta)
+	}
+
+	if errTestNotFound.Error() != "test item not found" || errTestNotFound.Code() != "test.not_found" || errTestNotFound.Kind() != NotFound {
+		t.Fatalf("expected the template accessors to return the definition")
+	}
+}
+
+func templateSourceIsCaller(t *testing.T) {
+	err := errTestNotFound.New()
+
+	var e *Error
+	As(err, &e)
+
+	if !strings.Contains(e.Source.String(), "template_test.go:") {
+		t.Fatalf("expected the source to point to the New() call, got: %s", e.Source)
+	}
+}
+
+func matchTemplateWithIs(t *testing.T) {
+	other := Define("test.other", "other")
+
+	err := fmt.Errorf("wrapped: %w", E("outer", errTestNotFound.New(WithMeta("id", 1))))
+
+	if !Is(err, errTestNotFound) {
+		t.Fatalf("expected Is() to match the template")
+	}
+
+	if Is(err, other) {
+		t.Fatalf("expected Is() not to match another template")
+	}
+
+	if !Is(M(errTestNotFound.New()), errTestNotFound) {
+		t.Fatalf("expected M() to keep the template")
+	}
+
+	if Is(E("test item not found"), errTestNotFound) {
+		t.Fatalf("expected Is() not to match errors with the same message")
+	}
+
+	if Is(E("other", Code("test.not_found")), errTestNotFound) {
+		t.Fatalf("expected Is() not to match errors with the same code")
+	}
+}
+
+func templateMetaNotShared(t *testing.T) {
+	e1 := errTestNotFound.New()
+	_, _ = MergeMeta(e1, WithMeta("added", true))
+
+	m, _ := GetMeta(errTestNotFound.New())
+	if _, has := m["added"]; has {
+		t.Fatalf("expected errors created from the template not to share Meta, got: %+v", m)
+	}
+}
+
+func matchDecodedTemplate(t *testing.T) {
+	b, _ := json.Marshal(errTestNotFound.New())
+
+	derr, err := FromJSON(b)
+	if err != nil {
+		t.Fatalf("expected FromJSON nil error, got: %s", err.Error())
+	}
+
+	if !Is(derr, errTestNotFound) {
+		t.Fatalf("expected the decoded error to match the template by code")
+	}
+}
@@ -0,0 +1,207 @@
+package errors
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode controls the ANSI colors in the PrettyTree output.
+type ColorMode uint8
+
+// Color modes.
+const (
+	ColorAuto   ColorMode = iota // Colors are enabled when writing to a terminal with FprintTree.
+	ColorAlways                  // Colors are always enabled.
+	ColorNever                   // Colors are always disabled.
+)
+
+// ANSI escape sequences used by the tree renderer.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiFaint = "\x1b[90m"
+)
+
+// TreeOptions configures PrettyTree and FprintTree.
+type TreeOptions struct {
+	// MaxDepth is the maximum depth of the wrapped errors rendered, deeper errors are replaced by "...". If it's zero or
+	// negative every error is rendered.
+	MaxDepth int
+
+	// HideMeta leaves out the Meta of the errors.
+	HideMeta bool
+
+	// ASCII renders the tree branches with ASCII characters instead of box-drawing characters.
+	ASCII bool
+
+	// Color controls the ANSI colors, see ColorMode.
+	Color ColorMode
+}
+
+// treeBranches holds the characters used to draw the tree.
+type treeBranches struct {
+	middle, last, line, space string
+}
+
+var (
+	boxBranches   = treeBranches{middle: "├── ", last: "└── ", line: "│   ", space: "    "}
+	asciiBranches = treeBranches{middle: "|-- ", last: "`-- ", line: "|   ", space: "    "}
+)
+
+// PrettyTree renders err as a tree where every cause is nested under the error wrapping it, including all branches of
+// errors with multiple causes. Each error lists its kind, code, source, meta and stack the same way as PrettyPrint.
+// The causes of a joined err, ex. returned by the stdlib errors.Join, are rendered as separate trees. opts can be nil.
+// This should only be used in development.
+func PrettyTree(err error, opts *TreeOptions) string {
+	if err == nil {
+		return ""
+	}
+
+	var o TreeOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	tr := treeRenderer{
+		opts:     o,
+		branches: boxBranches,
+		color:    o.Color == ColorAlways,
+	}
+
+	if o.ASCII {
+		tr.branches = asciiBranches
+	}
+
+	var b strings.Builder
+
+	// A joined root has no message of its own, its causes are rendered as separate trees.
+	if isJoin(err) {
+		for _, c := range childrenOf(err) {
+			tr.render(&b, c, "", "", 0)
+		}
+
+		return b.String()
+	}
+
+	tr.render(&b, err, "", "", 0)
+
+	return b.String()
+}
+
+// FprintTree writes the PrettyTree rendering of err to w. With ColorAuto colors are enabled when w is a terminal and
+// the NO_COLOR environment variable is not set.
+func FprintTree(w io.Writer, err error, opts *TreeOptions) error {
+	var o TreeOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Color == ColorAuto && isTerminal(w) {
+		o.Color = ColorAlways
+	}
+
+	_, werr := io.WriteString(w, PrettyTree(err, &o))
+
+	return werr
+}
+
+type treeRenderer struct {
+	opts     TreeOptions
+	branches treeBranches
+	color    bool
+}
+
+// render writes err and its causes to b. linePrefix is written before the message of err, childPrefix before every
+// other line belonging to err and its causes.
+func (tr *treeRenderer) render(b *strings.Builder, err error, linePrefix, childPrefix string, depth int) {
+	children := childrenOf(err)
+	truncated := tr.opts.MaxDepth > 0 && depth >= tr.opts.MaxDepth && len(children) > 0
+
+	b.WriteString(tr.paint(ansiFaint, linePrefix))
+	b.WriteString(tr.paint(ansiBold, err.Error()))
+	b.WriteByte('\n')
+
+	// The details are prefixed with a vertical line when there are causes rendered below them.
+	detailPrefix := childPrefix + " "
+	if len(children) > 0 {
+		detailPrefix = childPrefix + strings.TrimRight(tr.branches.line, " ")
+	}
+
+	for _, line := range tr.details(err) {
+		b.WriteString(tr.paint(ansiFaint, detailPrefix))
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	if truncated {
+		b.WriteString(tr.paint(ansiFaint, childPrefix+tr.branches.last+"..."))
+		b.WriteByte('\n')
+
+		return
+	}
+
+	for i, c := range children {
+		if i == len(children)-1 {
+			tr.render(b, c, childPrefix+tr.branches.last, childPrefix+tr.branches.space, depth+1)
+			continue
+		}
+
+		tr.render(b, c, childPrefix+tr.branches.middle, childPrefix+tr.branches.line, depth+1)
+	}
+}
+
+// details returns the lines rendered under the message of err, reusing the PrettyPrint pieces.
+func (tr *treeRenderer) details(err error) []string {
+	e, ok := err.(*Error)
+	if !ok {
+		return nil
+	}
+
+	var s strings.Builder
+
+	s.WriteString(publicPrettyString(e.PublicMsg))
+	s.WriteString(e.Kind.kindPrettyString())
+	s.WriteString(e.Code.codePrettyString())
+	s.WriteString(e.Source.sourcePrettyString())
+
+	if !tr.opts.HideMeta {
+		s.WriteString(e.Meta.metaPrettyString())
+	}
+
+	s.WriteString(e.Stack.stackPrettyString())
+
+	if s.Len() == 0 {
+		return nil
+	}
+
+	// Every piece starts with a new line.
+	return strings.Split(s.String()[1:], "\n")
+}
+
+func (tr *treeRenderer) paint(code, s string) string {
+	if !tr.color || len(s) == 0 {
+		return s
+	}
+
+	return code + s + ansiReset
+}
+
+// isTerminal reports whether w is a terminal that accepts colors.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrettyTree(t *testing.T) {
+	t.Run("it should nest causes under their parent", nestCauses)
+	t.Run("it should render ASCII branches", renderASCIIBranches)
+	t.Run("it should limit the depth", limitTreeDepth)
+	t.Run("it should hide meta", hideTreeMeta)
+	t.Run("it should paint colors on demand", paintTreeColors)
+	t.Run("it should not paint colors when not writing to a terminal", noColorsNonTerminal)
+	t.Run("it should render the causes of a joined root as trees", renderJoinedRoot)
+}
+
+func treeLines(s string) []string {
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func nestCauses(t *testing.T) {
+	err := E("root", E("a", E("a1")), errors.Join(errors.New("b"), E("c")))
+
+	var msgs []string
+	for _, l := range treeLines(PrettyTree(err, nil)) {
+		if !strings.Contains(l, "|- ") {
+			msgs = append(msgs, l)
+		}
+	}
+
+	want := []string{"root", "├── a", "│   └── a1", "├── b", "└── c"}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected tree:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(msgs, "\n"))
+	}
+
+	out := PrettyTree(err, nil)
+	if !strings.Contains(out, "│  |- Source : ") || !strings.Contains(out, "tree_test.go") {
+		t.Fatalf("expected the source under the message, got:\n%s", out)
+	}
+}
+
+func renderASCIIBranches(t *testing.T) {
+	out := PrettyTree(E("root", E("a"), E("b")), &TreeOptions{ASCII: true})
+
+	if !strings.Contains(out, "\n|-- a\n") || !strings.Contains(out, "\n`-- b\n") {
+		t.Fatalf("expected ASCII branches, got:\n%s", out)
+	}
+
+	if strings.ContainsAny(out, "├└│") {
+		t.Fatalf("expected no box-drawing characters, got:\n%s", out)
+	}
+}
+
+func limitTreeDepth(t *testing.T) {
+	out := PrettyTree(E("root", E("a", E("a1", E("a2")))), &TreeOptions{MaxDepth: 1})
+
+	if !strings.Contains(out, "└── a\n") || strings.Contains(out, "a1") {
+		t.Fatalf("expected the tree to stop at depth 1, got:\n%s", out)
+	}
+
+	if !strings.Contains(out, "└── ...") {
+		t.Fatalf("expected truncated causes to be marked, got:\n%s", out)
+	}
+}
+
+func hideTreeMeta(t *testing.T) {
+	err := E("root", WithMeta("secret", "value"))
+
+	if out := PrettyTree(err, nil); !strings.Contains(out, "|- secret : value") {
+		t.Fatalf("expected meta in the output, got:\n%s", out)
+	}
+
+	if out := PrettyTree(err, &TreeOptions{HideMeta: true}); strings.Contains(out, "secret") {
+		t.Fatalf("expected meta to be hidden, got:\n%s", out)
+	}
+}
+
+func paintTreeColors(t *testing.T) {
+	err := E("root", E("a"))
+
+	if out := PrettyTree(err, &TreeOptions{Color: ColorAlways}); !strings.Contains(out, ansiBold+"root"+ansiReset) {
+		t.Fatalf("expected colored output, got: %q", out)
+	}
+
+	if out := PrettyTree(err, nil); strings.Contains(out, "\x1b[") {
+		t.Fatalf("expected no colors by default, got: %q", out)
+	}
+}
+
+func noColorsNonTerminal(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := FprintTree(&buf, E("root"), nil); err != nil {
+		t.Fatalf("expected FprintTree nil error, got: %s", err.Error())
+	}
+
+	if strings.Contains(buf.String(), "\x1b[") || !strings.HasPrefix(buf.String(), "root\n") {
+		t.Fatalf("expected plain output, got: %q", buf.String())
+	}
+}
+
+func renderJoinedRoot(t *testing.T) {
+	err := errors.Join(E("a", E("a1")), errors.Join(E("b"), errors.New("c")))
+
+	var msgs []string
+	for _, l := range treeLines(PrettyTree(err, nil)) {
+		if !strings.Contains(l, "|- ") {
+			msgs = append(msgs, l)
+		}
+	}
+
+	want := []string{"a", "└── a1", "b", "c"}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected tree:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(msgs, "\n"))
+	}
+}